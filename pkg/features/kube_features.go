@@ -0,0 +1,59 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package features
+
+import (
+	"k8s.io/apimachinery/pkg/util/runtime"
+	utilfeature "k8s.io/apiserver/pkg/util/feature"
+	"k8s.io/component-base/featuregate"
+)
+
+const (
+	// owner: @klueska
+	// kep: https://kep.k8s.io/4033
+	// alpha: v1.26
+	//
+	// Allow fine-tuning of topology manager policies, experimental alpha-level options
+	TopologyManagerPolicyAlphaOptions featuregate.Feature = "TopologyManagerPolicyAlphaOptions"
+
+	// owner: @klueska
+	// kep: https://kep.k8s.io/4033
+	// beta: v1.26
+	//
+	// Allow fine-tuning of topology manager policies, experimental beta-level options
+	TopologyManagerPolicyBetaOptions featuregate.Feature = "TopologyManagerPolicyBetaOptions"
+
+	// owner: @fanzhangio
+	// alpha: v1.31
+	//
+	// Allow pods to request topology manager policy/scope/options that
+	// differ from the kubelet-wide defaults via a pod annotation.
+	TopologyManagerPerPodPolicy featuregate.Feature = "TopologyManagerPerPodPolicy"
+)
+
+// defaultKubernetesFeatureGates consists of all known Kubernetes-specific
+// feature keys used by the kubelet topology manager. To add a new feature,
+// define a key for it above and add it here.
+var defaultKubernetesFeatureGates = map[featuregate.Feature]featuregate.FeatureSpec{
+	TopologyManagerPolicyAlphaOptions: {Default: false, PreRelease: featuregate.Alpha},
+	TopologyManagerPolicyBetaOptions:  {Default: true, PreRelease: featuregate.Beta},
+	TopologyManagerPerPodPolicy:       {Default: false, PreRelease: featuregate.Alpha},
+}
+
+func init() {
+	runtime.Must(utilfeature.DefaultMutableFeatureGate.Add(defaultKubernetesFeatureGates))
+}
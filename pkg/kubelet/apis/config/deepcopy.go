@@ -0,0 +1,61 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+// DeepCopyInto is a deepcopy function, copying the receiver, writing into out.
+func (in *KubeletConfiguration) DeepCopyInto(out *KubeletConfiguration) {
+	*out = *in
+	if in.TopologyManagerPolicyOptions != nil {
+		out.TopologyManagerPolicyOptions = make(map[string]string, len(in.TopologyManagerPolicyOptions))
+		for k, v := range in.TopologyManagerPolicyOptions {
+			out.TopologyManagerPolicyOptions[k] = v
+		}
+	}
+	if in.TopologyManagerPolicyOptionsConfig != nil {
+		out.TopologyManagerPolicyOptionsConfig = new(TopologyManagerPolicyOptionsConfig)
+		in.TopologyManagerPolicyOptionsConfig.DeepCopyInto(out.TopologyManagerPolicyOptionsConfig)
+	}
+}
+
+// DeepCopy is a deepcopy function, copying the receiver, creating a new KubeletConfiguration.
+func (in *KubeletConfiguration) DeepCopy() *KubeletConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(KubeletConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is a deepcopy function, copying the receiver, writing into out.
+func (in *TopologyManagerPolicyOptionsConfig) DeepCopyInto(out *TopologyManagerPolicyOptionsConfig) {
+	*out = *in
+	if in.AllowedNUMANodes != nil {
+		out.AllowedNUMANodes = make([]int, len(in.AllowedNUMANodes))
+		copy(out.AllowedNUMANodes, in.AllowedNUMANodes)
+	}
+}
+
+// DeepCopy is a deepcopy function, copying the receiver, creating a new TopologyManagerPolicyOptionsConfig.
+func (in *TopologyManagerPolicyOptionsConfig) DeepCopy() *TopologyManagerPolicyOptionsConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(TopologyManagerPolicyOptionsConfig)
+	in.DeepCopyInto(out)
+	return out
+}
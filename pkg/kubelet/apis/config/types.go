@@ -0,0 +1,62 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+// KubeletConfiguration contains the configuration for the Kubelet. This
+// package currently only declares the topology-manager-related subset of
+// KubeletConfiguration; it is meant to be merged into, not shipped
+// alongside, the real KubeletConfiguration, which has many more fields plus
+// a versioned v1beta1 type, defaulter, and conversion functions.
+type KubeletConfiguration struct {
+	// TopologyManagerPolicy is the name of the topology manager policy to
+	// use.
+	TopologyManagerPolicy string
+	// TopologyManagerPolicyOptions is a set of key=value pairs that allow
+	// for the configuration of the topology manager policy.
+	TopologyManagerPolicyOptions map[string]string
+	// TopologyManagerPolicyOptionsConfig is the structured equivalent of
+	// TopologyManagerPolicyOptions. When set, it takes precedence over
+	// TopologyManagerPolicyOptions.
+	// +optional
+	TopologyManagerPolicyOptionsConfig *TopologyManagerPolicyOptionsConfig
+}
+
+// TopologyManagerPolicyOptionsConfig is the structured equivalent of the
+// comma-separated key=value pairs accepted by the
+// --topology-manager-policy-options flag. Every field mirrors the topology
+// manager policy option of the same name; see
+// pkg/kubelet/cm/topologymanager.PolicyOptions for their semantics.
+type TopologyManagerPolicyOptionsConfig struct {
+	// PreferClosestNUMANodes corresponds to the prefer-closest-numa-nodes option.
+	// +optional
+	PreferClosestNUMANodes bool
+	// MaxAllowableNUMANodes corresponds to the max-allowable-numa-nodes option.
+	// +optional
+	MaxAllowableNUMANodes int
+	// AllowedNUMANodes corresponds to the allowed-numa-nodes option.
+	// +optional
+	AllowedNUMANodes []int
+	// SingleNUMANodeExclusive corresponds to the single-numa-node-exclusive option.
+	// +optional
+	SingleNUMANodeExclusive bool
+	// NUMADistanceWeight corresponds to the numa-distance-weight option.
+	// +optional
+	NUMADistanceWeight string
+	// NUMADistanceThreshold corresponds to the numa-distance-threshold option.
+	// +optional
+	NUMADistanceThreshold int
+}
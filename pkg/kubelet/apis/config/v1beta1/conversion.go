@@ -0,0 +1,70 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"k8s.io/kubernetes/pkg/kubelet/apis/config"
+)
+
+// Convert_v1beta1_KubeletConfiguration_To_config_KubeletConfiguration
+// converts the topology-manager-related fields of a versioned
+// KubeletConfiguration to their internal counterpart. It is meant to be
+// merged into, not shipped alongside, the real conversion function, which
+// also converts every other field.
+func Convert_v1beta1_KubeletConfiguration_To_config_KubeletConfiguration(in *KubeletConfiguration, out *config.KubeletConfiguration) error {
+	out.TopologyManagerPolicy = in.TopologyManagerPolicy
+	out.TopologyManagerPolicyOptions = in.TopologyManagerPolicyOptions
+	out.TopologyManagerPolicyOptionsConfig = convertV1beta1PolicyOptionsConfigToConfig(in.TopologyManagerPolicyOptionsConfig)
+	return nil
+}
+
+// Convert_config_KubeletConfiguration_To_v1beta1_KubeletConfiguration is
+// the inverse of Convert_v1beta1_KubeletConfiguration_To_config_KubeletConfiguration.
+func Convert_config_KubeletConfiguration_To_v1beta1_KubeletConfiguration(in *config.KubeletConfiguration, out *KubeletConfiguration) error {
+	out.TopologyManagerPolicy = in.TopologyManagerPolicy
+	out.TopologyManagerPolicyOptions = in.TopologyManagerPolicyOptions
+	out.TopologyManagerPolicyOptionsConfig = convertConfigPolicyOptionsConfigToV1beta1(in.TopologyManagerPolicyOptionsConfig)
+	return nil
+}
+
+func convertV1beta1PolicyOptionsConfigToConfig(in *TopologyManagerPolicyOptionsConfig) *config.TopologyManagerPolicyOptionsConfig {
+	if in == nil {
+		return nil
+	}
+	return &config.TopologyManagerPolicyOptionsConfig{
+		PreferClosestNUMANodes:  in.PreferClosestNUMANodes,
+		MaxAllowableNUMANodes:   in.MaxAllowableNUMANodes,
+		AllowedNUMANodes:        in.AllowedNUMANodes,
+		SingleNUMANodeExclusive: in.SingleNUMANodeExclusive,
+		NUMADistanceWeight:      in.NUMADistanceWeight,
+		NUMADistanceThreshold:   in.NUMADistanceThreshold,
+	}
+}
+
+func convertConfigPolicyOptionsConfigToV1beta1(in *config.TopologyManagerPolicyOptionsConfig) *TopologyManagerPolicyOptionsConfig {
+	if in == nil {
+		return nil
+	}
+	return &TopologyManagerPolicyOptionsConfig{
+		PreferClosestNUMANodes:  in.PreferClosestNUMANodes,
+		MaxAllowableNUMANodes:   in.MaxAllowableNUMANodes,
+		AllowedNUMANodes:        in.AllowedNUMANodes,
+		SingleNUMANodeExclusive: in.SingleNUMANodeExclusive,
+		NUMADistanceWeight:      in.NUMADistanceWeight,
+		NUMADistanceThreshold:   in.NUMADistanceThreshold,
+	}
+}
@@ -0,0 +1,63 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"reflect"
+	"testing"
+
+	"k8s.io/kubernetes/pkg/kubelet/apis/config"
+)
+
+func TestKubeletConfigurationConversionRoundTrip(t *testing.T) {
+	original := &KubeletConfiguration{
+		TopologyManagerPolicy:        "best-effort",
+		TopologyManagerPolicyOptions: map[string]string{"prefer-closest-numa-nodes": "true"},
+		TopologyManagerPolicyOptionsConfig: &TopologyManagerPolicyOptionsConfig{
+			AllowedNUMANodes:      []int{0, 1},
+			MaxAllowableNUMANodes: 4,
+		},
+	}
+
+	internal := &config.KubeletConfiguration{}
+	if err := Convert_v1beta1_KubeletConfiguration_To_config_KubeletConfiguration(original, internal); err != nil {
+		t.Fatalf("unexpected error converting to internal: %v", err)
+	}
+
+	roundTripped := &KubeletConfiguration{}
+	if err := Convert_config_KubeletConfiguration_To_v1beta1_KubeletConfiguration(internal, roundTripped); err != nil {
+		t.Fatalf("unexpected error converting back to v1beta1: %v", err)
+	}
+
+	if !reflect.DeepEqual(original, roundTripped) {
+		t.Errorf("expected round trip to preserve the original, got %+v after converting to %+v", roundTripped, internal)
+	}
+}
+
+func TestSetDefaultsKubeletConfiguration(t *testing.T) {
+	obj := &KubeletConfiguration{}
+	SetDefaults_KubeletConfiguration(obj)
+	if obj.TopologyManagerPolicy != defaultTopologyManagerPolicy {
+		t.Errorf("expected default TopologyManagerPolicy %q, got %q", defaultTopologyManagerPolicy, obj.TopologyManagerPolicy)
+	}
+
+	obj = &KubeletConfiguration{TopologyManagerPolicy: "restricted"}
+	SetDefaults_KubeletConfiguration(obj)
+	if obj.TopologyManagerPolicy != "restricted" {
+		t.Errorf("expected an already-set TopologyManagerPolicy to be left alone, got %q", obj.TopologyManagerPolicy)
+	}
+}
@@ -0,0 +1,31 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+// defaultTopologyManagerPolicy is used when TopologyManagerPolicy is left
+// unset, matching the topology manager's own "no policy in effect" default.
+const defaultTopologyManagerPolicy = "none"
+
+// SetDefaults_KubeletConfiguration applies defaults to the
+// topology-manager-related fields of obj. It is meant to be merged into,
+// not shipped alongside, the real SetDefaults_KubeletConfiguration, which
+// also defaults every other field of the real KubeletConfiguration.
+func SetDefaults_KubeletConfiguration(obj *KubeletConfiguration) {
+	if obj.TopologyManagerPolicy == "" {
+		obj.TopologyManagerPolicy = defaultTopologyManagerPolicy
+	}
+}
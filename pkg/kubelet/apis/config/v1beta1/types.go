@@ -0,0 +1,63 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package v1beta1 contains the versioned, YAML/JSON-serializable
+// counterpart of the topology-manager-related subset of
+// k8s.io/kubernetes/pkg/kubelet/apis/config.KubeletConfiguration declared
+// in this trimmed checkout. It is meant to be merged into, not shipped
+// alongside, the real v1beta1.KubeletConfiguration.
+package v1beta1
+
+// KubeletConfiguration is the versioned counterpart of
+// config.KubeletConfiguration.
+type KubeletConfiguration struct {
+	// TopologyManagerPolicy is the name of the topology manager policy to
+	// use.
+	// +optional
+	TopologyManagerPolicy string `json:"topologyManagerPolicy,omitempty"`
+	// TopologyManagerPolicyOptions is a set of key=value pairs that allow
+	// for the configuration of the topology manager policy.
+	// +optional
+	TopologyManagerPolicyOptions map[string]string `json:"topologyManagerPolicyOptions,omitempty"`
+	// TopologyManagerPolicyOptionsConfig is the structured equivalent of
+	// TopologyManagerPolicyOptions. When set, it takes precedence over
+	// TopologyManagerPolicyOptions.
+	// +optional
+	TopologyManagerPolicyOptionsConfig *TopologyManagerPolicyOptionsConfig `json:"topologyManagerPolicyOptionsConfig,omitempty"`
+}
+
+// TopologyManagerPolicyOptionsConfig is the versioned counterpart of
+// config.TopologyManagerPolicyOptionsConfig.
+type TopologyManagerPolicyOptionsConfig struct {
+	// PreferClosestNUMANodes corresponds to the prefer-closest-numa-nodes option.
+	// +optional
+	PreferClosestNUMANodes bool `json:"preferClosestNUMANodes,omitempty"`
+	// MaxAllowableNUMANodes corresponds to the max-allowable-numa-nodes option.
+	// +optional
+	MaxAllowableNUMANodes int `json:"maxAllowableNUMANodes,omitempty"`
+	// AllowedNUMANodes corresponds to the allowed-numa-nodes option.
+	// +optional
+	AllowedNUMANodes []int `json:"allowedNUMANodes,omitempty"`
+	// SingleNUMANodeExclusive corresponds to the single-numa-node-exclusive option.
+	// +optional
+	SingleNUMANodeExclusive bool `json:"singleNUMANodeExclusive,omitempty"`
+	// NUMADistanceWeight corresponds to the numa-distance-weight option.
+	// +optional
+	NUMADistanceWeight string `json:"numaDistanceWeight,omitempty"`
+	// NUMADistanceThreshold corresponds to the numa-distance-threshold option.
+	// +optional
+	NUMADistanceThreshold int `json:"numaDistanceThreshold,omitempty"`
+}
@@ -0,0 +1,51 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validation
+
+import (
+	"fmt"
+
+	"k8s.io/kubernetes/pkg/kubelet/apis/config"
+	"k8s.io/kubernetes/pkg/kubelet/cm/topologymanager"
+)
+
+// ValidateKubeletConfiguration validates kc. This package currently only
+// covers the topology-manager-related fields of KubeletConfiguration; it is
+// meant to be merged into, not shipped alongside, the full
+// ValidateKubeletConfiguration (which also validates every other field of
+// the real, much larger KubeletConfiguration).
+func ValidateKubeletConfiguration(kc *config.KubeletConfiguration) error {
+	if err := validateTopologyManagerPolicyOptionsConfig(kc); err != nil {
+		return fmt.Errorf("invalid KubeletConfiguration: %w", err)
+	}
+	return nil
+}
+
+// validateTopologyManagerPolicyOptionsConfig runs
+// kc.TopologyManagerPolicyOptionsConfig through the same validation the
+// topology manager itself applies, so a malformed value is rejected at
+// kubelet-configuration load time rather than only when the topology
+// manager is created from it.
+func validateTopologyManagerPolicyOptionsConfig(kc *config.KubeletConfiguration) error {
+	if kc.TopologyManagerPolicyOptionsConfig == nil {
+		return nil
+	}
+	if _, err := topologymanager.NewPolicyOptionsFromConfig(kc.TopologyManagerPolicyOptionsConfig); err != nil {
+		return fmt.Errorf("invalid topologyManagerPolicyOptionsConfig: %w", err)
+	}
+	return nil
+}
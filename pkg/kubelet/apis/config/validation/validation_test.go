@@ -0,0 +1,78 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validation
+
+import (
+	"strings"
+	"testing"
+
+	"k8s.io/kubernetes/pkg/kubelet/apis/config"
+)
+
+func TestValidateKubeletConfiguration(t *testing.T) {
+	testCases := []struct {
+		description string
+		kc          *config.KubeletConfiguration
+		expectedErr string
+	}{
+		{
+			description: "nil TopologyManagerPolicyOptionsConfig is valid",
+			kc:          &config.KubeletConfiguration{},
+		},
+		{
+			description: "valid TopologyManagerPolicyOptionsConfig",
+			kc: &config.KubeletConfiguration{
+				TopologyManagerPolicyOptionsConfig: &config.TopologyManagerPolicyOptionsConfig{
+					AllowedNUMANodes: []int{0, 1},
+				},
+			},
+		},
+		{
+			description: "duplicate AllowedNUMANodes entry is rejected",
+			kc: &config.KubeletConfiguration{
+				TopologyManagerPolicyOptionsConfig: &config.TopologyManagerPolicyOptionsConfig{
+					AllowedNUMANodes: []int{0, 0},
+				},
+			},
+			expectedErr: "duplicate NUMA node ID",
+		},
+		{
+			description: "negative AllowedNUMANodes entry is rejected",
+			kc: &config.KubeletConfiguration{
+				TopologyManagerPolicyOptionsConfig: &config.TopologyManagerPolicyOptionsConfig{
+					AllowedNUMANodes: []int{-1},
+				},
+			},
+			expectedErr: "non-negative",
+		},
+	}
+
+	for _, tcase := range testCases {
+		t.Run(tcase.description, func(t *testing.T) {
+			err := ValidateKubeletConfiguration(tcase.kc)
+			if tcase.expectedErr == "" {
+				if err != nil {
+					t.Fatalf("Unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tcase.expectedErr) {
+				t.Errorf("Unexpected error. Have: %v, wants %s", err, tcase.expectedErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,253 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bitmask
+
+import (
+	"fmt"
+	"math/bits"
+	"strconv"
+	"strings"
+)
+
+// BitMask interface allows hint providers to create BitMasks for TopologyHints
+type BitMask interface {
+	Add(bits ...int) error
+	Remove(bits ...int) error
+	And(masks ...BitMask)
+	Or(masks ...BitMask)
+	Clear()
+	Fill()
+	IsEqual(mask BitMask) bool
+	IsEmpty() bool
+	IsSet(bit int) bool
+	AnySet(bits []int) bool
+	IsNarrowerThan(mask BitMask) bool
+	String() string
+	Count() int
+	GetBits() []int
+}
+
+type bitMask uint64
+
+// NewEmptyBitMask creates a new, empty BitMask.
+func NewEmptyBitMask() BitMask {
+	s := bitMask(0)
+	return &s
+}
+
+// NewBitMask creates a new BitMask populated with the bits passed in as
+// arguments.
+func NewBitMask(bits ...int) (BitMask, error) {
+	s := bitMask(0)
+	err := (&s).Add(bits...)
+	if err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// Add adds the bits with the topology affinity to the BitMask.
+func (s *bitMask) Add(bits ...int) error {
+	mask := *s
+	for _, i := range bits {
+		if i < 0 || i >= 64 {
+			return fmt.Errorf("bit number must be in range 0-63")
+		}
+		mask |= 1 << uint64(i)
+	}
+	*s = mask
+	return nil
+}
+
+// Remove removes the bits with the topology affinity from the BitMask.
+func (s *bitMask) Remove(bits ...int) error {
+	mask := *s
+	for _, i := range bits {
+		if i < 0 || i >= 64 {
+			return fmt.Errorf("bit number must be in range 0-63")
+		}
+		mask &^= 1 << uint64(i)
+	}
+	*s = mask
+	return nil
+}
+
+// And performs and operation on all bits in masks. A nil or foreign-typed
+// mask fails closed: it ANDs in zero bits rather than panicking, so a
+// caller that ends up with an invalid mask gets "no NUMA node allowed"
+// instead of a crash.
+func (s *bitMask) And(masks ...BitMask) {
+	for _, m := range masks {
+		bm, ok := m.(*bitMask)
+		if !ok {
+			*s = 0
+			continue
+		}
+		*s &= bm.clone()
+	}
+}
+
+// Or performs or operation on all bits in masks. A nil or foreign-typed
+// mask contributes no bits rather than panicking.
+func (s *bitMask) Or(masks ...BitMask) {
+	for _, m := range masks {
+		bm, ok := m.(*bitMask)
+		if !ok {
+			continue
+		}
+		*s |= bm.clone()
+	}
+}
+
+// Clear resets all bits in the BitMask to zero.
+func (s *bitMask) Clear() {
+	*s = 0
+}
+
+// Fill sets all bits in the BitMask to one.
+func (s *bitMask) Fill() {
+	*s = bitMask(^uint64(0))
+}
+
+// IsEmpty checks if all bits in the BitMask are zero.
+func (s *bitMask) IsEmpty() bool {
+	return *s == 0
+}
+
+// IsSet checks if a specific bit is set in the BitMask.
+func (s *bitMask) IsSet(bit int) bool {
+	if bit < 0 || bit >= 64 {
+		return false
+	}
+	return (*s & (1 << uint64(bit))) > 0
+}
+
+// AnySet checks if any of the bits passed in are set in the BitMask.
+func (s *bitMask) AnySet(bits []int) bool {
+	for _, b := range bits {
+		if s.IsSet(b) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsEqual checks if another BitMask is equal to this BitMask.
+func (s *bitMask) IsEqual(mask BitMask) bool {
+	return *s == *mask.(*bitMask)
+}
+
+// IsNarrowerThan checks if this BitMask is narrower than another BitMask. A
+// BitMask is narrower than another if it has fewer bits set, or if it has
+// the same number of bits set, but a lower-numbered first bit set.
+func (s *bitMask) IsNarrowerThan(mask BitMask) bool {
+	if s.Count() == mask.Count() {
+		if s.getOnesIndexStart() < mask.(*bitMask).getOnesIndexStart() {
+			return true
+		}
+		return false
+	}
+	return s.Count() < mask.Count()
+}
+
+// String converts BitMask to string.
+func (s *bitMask) String() string {
+	grouping := 2
+	for shift := 64 - grouping; shift > 0; shift -= grouping {
+		if *s > (1 << uint(shift)) {
+			return fmt.Sprintf(fmt.Sprintf("%%0%db", shift+grouping), *s)
+		}
+	}
+	return fmt.Sprintf(fmt.Sprintf("%%0%db", grouping), *s)
+}
+
+// Count counts the number of bits set in the BitMask.
+func (s *bitMask) Count() int {
+	return bits.OnesCount64(uint64(*s))
+}
+
+// GetBits returns each bit number with bits set in the BitMask.
+func (s *bitMask) GetBits() []int {
+	var bits []int
+	for i := 0; i < 64; i++ {
+		if (*s & (1 << uint64(i))) > 0 {
+			bits = append(bits, i)
+		}
+	}
+	return bits
+}
+
+func (s *bitMask) getOnesIndexStart() int {
+	for i := 0; i < 64; i++ {
+		if s.IsSet(i) {
+			return i
+		}
+	}
+	return 64
+}
+
+func (s *bitMask) clone() bitMask {
+	clone := *s
+	return clone
+}
+
+// IterateBitMasks iterates all possible masks from a list of bits,
+// issuing a callback on each mask.
+func IterateBitMasks(bits []int, callback func(BitMask)) {
+	if len(bits) == 0 {
+		return
+	}
+
+	// Create a mask to hold the result.
+	mask, _ := NewBitMask()
+
+	// Define a recursive helper function to help generate all of the
+	// combinations of bits in the mask.
+	var iterate func(start, size int)
+	iterate = func(start, size int) {
+		if size == 0 {
+			callback(mask)
+			return
+		}
+		for i := start; i <= len(bits)-size; i++ {
+			mask.Add(bits[i])
+			iterate(i+1, size-1)
+			mask.Remove(bits[i])
+		}
+	}
+
+	for i := 1; i <= len(bits); i++ {
+		iterate(0, i)
+	}
+}
+
+// Parse builds a BitMask from a human-readable comma separated list of NUMA
+// node IDs. An empty or all-whitespace string yields an empty BitMask.
+func Parse(s string) (BitMask, error) {
+	if strings.TrimSpace(s) == "" {
+		return NewEmptyBitMask(), nil
+	}
+	var ids []int
+	for _, tok := range strings.Split(s, ",") {
+		v, err := strconv.Atoi(strings.TrimSpace(tok))
+		if err != nil {
+			return nil, fmt.Errorf("invalid NUMA node ID %q: %w", tok, err)
+		}
+		ids = append(ids, v)
+	}
+	return NewBitMask(ids...)
+}
@@ -0,0 +1,123 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package topologymanager
+
+// HintMerger computes the best TopologyHint across every hint provider for
+// a single policy invocation. It is constructed fresh for every Merge()
+// call so that it can be threaded with the PolicyOptions in effect for the
+// pod currently being admitted.
+type HintMerger struct {
+	NUMAInfo       *NUMAInfo
+	ProvidersHints []map[string][]TopologyHint
+	Policy         string
+	Opts           PolicyOptions
+}
+
+// NewHintMerger returns a HintMerger ready to compute the best TopologyHint
+// for the given providers' hints under opts.
+func NewHintMerger(numaInfo *NUMAInfo, providersHints []map[string][]TopologyHint, policy string, opts PolicyOptions) HintMerger {
+	return HintMerger{
+		NUMAInfo:       numaInfo,
+		ProvidersHints: providersHints,
+		Policy:         policy,
+		Opts:           opts,
+	}
+}
+
+// Merge returns the best TopologyHint given the current set of provider
+// hints, by iterating over every permutation (one hint per provider) and
+// keeping the one that is most preferred.
+func (m HintMerger) Merge() TopologyHint {
+	filteredProvidersHints := filterProvidersHints(m.ProvidersHints)
+	filteredProvidersHints = filterHintsByAllowedNUMANodes(filteredProvidersHints, m.Opts.AllowedNUMANodes)
+
+	bestHint := TopologyHint{defaultAffinity(m.NUMAInfo, m.Opts), false}
+
+	iterateAllProviderTopologyHints(filteredProvidersHints, func(permutation []TopologyHint) {
+		mergedHint := mergePermutation(m.NUMAInfo, m.Opts, permutation)
+		if m.Opts.MaxAllowableNUMANodes > 0 && mergedHint.NUMANodeAffinity.Count() > m.Opts.MaxAllowableNUMANodes {
+			return
+		}
+		if mergedHint.NUMANodeAffinity == nil || mergedHint.NUMANodeAffinity.IsEmpty() {
+			return
+		}
+		if bestHint.NUMANodeAffinity == nil || betterHint(m.NUMAInfo, m.Opts, mergedHint, bestHint) {
+			bestHint = mergedHint
+		}
+	})
+
+	return bestHint
+}
+
+// betterHint returns true if candidate should replace current as the best
+// hint found so far. Hints are ordered by: preferred first, then by fewest
+// NUMA nodes spanned, then — when opts.NUMADistanceWeight selects a
+// weighting mode — by lowest pairwise NUMA distance cost. Without a
+// weighting mode, ties are broken by lowest linear distance cost when
+// opts.PreferClosestNUMA is set, and then as TopologyHint.LessThan does,
+// by the narrower affinity. LessThan itself knows nothing about NUMA
+// distances.
+func betterHint(numaInfo *NUMAInfo, opts PolicyOptions, candidate, current TopologyHint) bool {
+	if candidate.Preferred != current.Preferred {
+		return candidate.Preferred
+	}
+
+	candidateCount := candidate.NUMANodeAffinity.Count()
+	currentCount := current.NUMANodeAffinity.Count()
+	if candidateCount != currentCount {
+		return candidateCount < currentCount
+	}
+
+	if opts.NUMADistanceWeight == "" || opts.NUMADistanceWeight == NUMADistanceWeightNone {
+		if opts.PreferClosestNUMA {
+			candidateCost := numaDistanceCost(numaInfo, candidate.NUMANodeAffinity, NUMADistanceWeightLinear)
+			currentCost := numaDistanceCost(numaInfo, current.NUMANodeAffinity, NUMADistanceWeightLinear)
+			if candidateCost != currentCost {
+				return candidateCost < currentCost
+			}
+		}
+		return candidate.NUMANodeAffinity.IsNarrowerThan(current.NUMANodeAffinity)
+	}
+
+	return numaDistanceCost(numaInfo, candidate.NUMANodeAffinity, opts.NUMADistanceWeight) <
+		numaDistanceCost(numaInfo, current.NUMANodeAffinity, opts.NUMADistanceWeight)
+}
+
+// iterateAllProviderTopologyHints calls callback once for every combination
+// of hints where exactly one hint is chosen from each provider's slice
+// (the cross product across providers).
+func iterateAllProviderTopologyHints(providersHints [][]TopologyHint, callback func(permutation []TopologyHint)) {
+	permutation := make([]TopologyHint, len(providersHints))
+
+	var iterate func(providerIndex int)
+	iterate = func(providerIndex int) {
+		if providerIndex == len(providersHints) {
+			callback(permutation)
+			return
+		}
+
+		for _, hint := range providersHints[providerIndex] {
+			permutation[providerIndex] = hint
+			iterate(providerIndex + 1)
+		}
+	}
+
+	if len(providersHints) == 0 {
+		return
+	}
+	iterate(0)
+}
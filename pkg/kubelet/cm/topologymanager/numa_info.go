@@ -0,0 +1,69 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package topologymanager
+
+import (
+	"fmt"
+
+	cadvisorapi "github.com/google/cadvisor/info/v1"
+)
+
+// NUMAInfo contains information about the node's NUMA topology as reported
+// by cadvisor, as well as the list of NUMA nodes available on the system.
+type NUMAInfo struct {
+	Nodes []int
+	// NUMADistances[i][j] is the distance from NUMA node i to NUMA node j,
+	// as reported by cadvisor. It is nil if the underlying machine did not
+	// report a distance matrix.
+	NUMADistances map[int][]int
+}
+
+// NewNUMAInfo returns a new NUMAInfo, built from the NUMA topology reported
+// by cadvisor for the local machine.
+func NewNUMAInfo(topology []cadvisorapi.Node, opts PolicyOptions) (*NUMAInfo, error) {
+	var nodes []int
+	var distances map[int][]int
+	for _, node := range topology {
+		nodes = append(nodes, node.Id)
+		if len(node.Distances) == 0 {
+			continue
+		}
+		if distances == nil {
+			distances = map[int][]int{}
+		}
+		distances[node.Id] = node.Distances
+	}
+
+	if opts.NUMADistanceWeight != "" && opts.NUMADistanceWeight != NUMADistanceWeightNone && len(distances) == 0 {
+		return nil, fmt.Errorf("NUMA distance weight mode %q requires a NUMA distance matrix, but cadvisor did not report one for this machine", opts.NUMADistanceWeight)
+	}
+
+	return &NUMAInfo{
+		Nodes:         nodes,
+		NUMADistances: distances,
+	}, nil
+}
+
+// Distance returns the distance between NUMA node a and NUMA node b as
+// reported by cadvisor. It returns 0 if the distance is unknown.
+func (n *NUMAInfo) Distance(a, b int) uint64 {
+	row, ok := n.NUMADistances[a]
+	if !ok || b < 0 || b >= len(row) {
+		return 0
+	}
+	return uint64(row[b])
+}
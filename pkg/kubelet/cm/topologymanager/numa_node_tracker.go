@@ -0,0 +1,152 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package topologymanager
+
+import (
+	"sync"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+// NUMANodeStatus describes how a NUMA node's resources are currently held
+// by admitted pods, for the purposes of the SingleNUMANodeExclusive
+// PolicyOption.
+type NUMANodeStatus string
+
+const (
+	// NUMANodeStatusIdle means no admitted pod currently uses the node.
+	NUMANodeStatusIdle NUMANodeStatus = "Idle"
+	// NUMANodeStatusSingle means a pod holds the node through a
+	// single-NUMA-node hint. Hints are recorded for every admitted pod,
+	// whether or not it requested SingleNUMANodeExclusive.
+	NUMANodeStatusSingle NUMANodeStatus = "Single"
+	// NUMANodeStatusShared means the node is part of one or more
+	// multi-NUMA-node hints and is not held exclusively by any pod.
+	NUMANodeStatusShared NUMANodeStatus = "Shared"
+)
+
+// numaNodeTracker tracks, for every NUMA node on the host, whether it is
+// Idle, held exclusively by a single pod (Single), or shared across
+// multiple multi-NUMA-node hints (Shared). The topology manager consults it
+// while admitting a pod that set SingleNUMANodeExclusive, and updates it
+// once AddContainer confirms the admission actually went through.
+type numaNodeTracker struct {
+	mu sync.Mutex
+	// exclusiveHolder[node] is the pod UID that was granted exclusive use
+	// of node, if any.
+	exclusiveHolder map[int]string
+	// sharedHolders[node] is the set of pod UIDs whose multi-NUMA-node
+	// hint includes node.
+	sharedHolders map[int]sets.Set[string]
+}
+
+func newNUMANodeTracker() *numaNodeTracker {
+	return &numaNodeTracker{
+		exclusiveHolder: map[int]string{},
+		sharedHolders:   map[int]sets.Set[string]{},
+	}
+}
+
+// status returns the current NUMANodeStatus of the given NUMA node.
+func (t *numaNodeTracker) status(node int) NUMANodeStatus {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.statusLocked(node)
+}
+
+func (t *numaNodeTracker) statusLocked(node int) NUMANodeStatus {
+	if _, ok := t.exclusiveHolder[node]; ok {
+		return NUMANodeStatusSingle
+	}
+	if holders, ok := t.sharedHolders[node]; ok && holders.Len() > 0 {
+		return NUMANodeStatusShared
+	}
+	return NUMANodeStatusIdle
+}
+
+// canAdmit reports whether hint is compatible with the current exclusivity
+// state of every NUMA node it touches: a single-NUMA-node hint is rejected
+// if the node is already Shared, and a multi-NUMA-node hint is rejected if
+// any of its nodes is already held Single.
+func (t *numaNodeTracker) canAdmit(hint TopologyHint) bool {
+	if hint.NUMANodeAffinity == nil {
+		return true
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	bits := hint.NUMANodeAffinity.GetBits()
+	single := len(bits) == 1
+	for _, node := range bits {
+		switch t.statusLocked(node) {
+		case NUMANodeStatusShared:
+			if single {
+				return false
+			}
+		case NUMANodeStatusSingle:
+			if !single {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// record commits podUID's admitted hint into the tracker, transitioning
+// every NUMA node it touches to Single (if hint is a single-NUMA-node hint)
+// or Shared (otherwise).
+func (t *numaNodeTracker) record(podUID string, hint TopologyHint) {
+	if hint.NUMANodeAffinity == nil {
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	bits := hint.NUMANodeAffinity.GetBits()
+	single := len(bits) == 1
+	for _, node := range bits {
+		if single {
+			t.exclusiveHolder[node] = podUID
+			continue
+		}
+		if t.sharedHolders[node] == nil {
+			t.sharedHolders[node] = sets.New[string]()
+		}
+		t.sharedHolders[node].Insert(podUID)
+	}
+}
+
+// release forgets any claim podUID holds on any NUMA node, exclusive or
+// shared. It is called once a pod's last container is removed.
+func (t *numaNodeTracker) release(podUID string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for node, holder := range t.exclusiveHolder {
+		if holder == podUID {
+			delete(t.exclusiveHolder, node)
+		}
+	}
+	for node, holders := range t.sharedHolders {
+		holders.Delete(podUID)
+		if holders.Len() == 0 {
+			delete(t.sharedHolders, node)
+		}
+	}
+}
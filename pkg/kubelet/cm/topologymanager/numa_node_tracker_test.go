@@ -0,0 +1,183 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package topologymanager
+
+import (
+	"fmt"
+	"testing"
+
+	utilfeature "k8s.io/apiserver/pkg/util/feature"
+	featuregatetesting "k8s.io/component-base/featuregate/testing"
+	pkgfeatures "k8s.io/kubernetes/pkg/features"
+
+	"k8s.io/kubernetes/pkg/kubelet/cm/topologymanager/bitmask"
+)
+
+func TestNewPolicyOptionsSingleNUMANodeExclusive(t *testing.T) {
+	testCases := []struct {
+		description       string
+		policyOptions     map[string]string
+		featureGateEnable bool
+		expectedErr       error
+		expectedOptions   PolicyOptions
+	}{
+		{
+			description:       "return PolicyOptions with SingleNUMANodeExclusive set to true",
+			featureGateEnable: true,
+			policyOptions: map[string]string{
+				SingleNUMANodeExclusive: "true",
+			},
+			expectedOptions: PolicyOptions{
+				MaxAllowableNUMANodes:   defaultMaxAllowableNUMANodes,
+				SingleNUMANodeExclusive: true,
+			},
+		},
+		{
+			description: "fail to set SingleNUMANodeExclusive when TopologyManagerPolicyAlphaOptions feature gate is not set",
+			policyOptions: map[string]string{
+				SingleNUMANodeExclusive: "true",
+			},
+			expectedErr: fmt.Errorf("topology manager policy alpha-level options not enabled,"),
+		},
+		{
+			description:       "fail to parse SingleNUMANodeExclusive with a non-boolean value",
+			featureGateEnable: true,
+			policyOptions: map[string]string{
+				SingleNUMANodeExclusive: "not-a-bool",
+			},
+			expectedErr: fmt.Errorf("bad value for option"),
+		},
+	}
+
+	for _, tcase := range testCases {
+		t.Run(tcase.description, func(t *testing.T) {
+			featuregatetesting.SetFeatureGateDuringTest(t, utilfeature.DefaultFeatureGate, pkgfeatures.TopologyManagerPolicyAlphaOptions, tcase.featureGateEnable)
+
+			opts, err := NewPolicyOptions(tcase.policyOptions)
+			if tcase.expectedErr != nil {
+				if err == nil {
+					t.Fatalf("expected error %v, got nil", tcase.expectedErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if opts.SingleNUMANodeExclusive != tcase.expectedOptions.SingleNUMANodeExclusive {
+				t.Errorf("expected SingleNUMANodeExclusive %v, got %v", tcase.expectedOptions.SingleNUMANodeExclusive, opts.SingleNUMANodeExclusive)
+			}
+		})
+	}
+}
+
+func hintFor(bits ...int) TopologyHint {
+	mask, _ := bitmask.NewBitMask(bits...)
+	return TopologyHint{NUMANodeAffinity: mask, Preferred: true}
+}
+
+func TestNUMANodeTrackerAdmissionMatrix(t *testing.T) {
+	testCases := []struct {
+		description  string
+		priorRecords []struct {
+			podUID string
+			hint   TopologyHint
+		}
+		candidate     TopologyHint
+		expectedAdmit bool
+	}{
+		{
+			description:   "Idle node accepts a single-NUMA-node hint",
+			candidate:     hintFor(0),
+			expectedAdmit: true,
+		},
+		{
+			description:   "Idle node accepts a multi-NUMA-node hint",
+			candidate:     hintFor(0, 1),
+			expectedAdmit: true,
+		},
+		{
+			description: "node already Single rejects a multi-NUMA-node hint that intersects it",
+			priorRecords: []struct {
+				podUID string
+				hint   TopologyHint
+			}{
+				{"pod-a", hintFor(0)},
+			},
+			candidate:     hintFor(0, 1),
+			expectedAdmit: false,
+		},
+		{
+			description: "node already Shared rejects a single-NUMA-node hint for it",
+			priorRecords: []struct {
+				podUID string
+				hint   TopologyHint
+			}{
+				{"pod-a", hintFor(0, 1)},
+			},
+			candidate:     hintFor(0),
+			expectedAdmit: false,
+		},
+		{
+			description: "node already Single accepts another single-NUMA-node hint for the same node",
+			priorRecords: []struct {
+				podUID string
+				hint   TopologyHint
+			}{
+				{"pod-a", hintFor(0)},
+			},
+			candidate:     hintFor(0),
+			expectedAdmit: true,
+		},
+		{
+			description: "node already Shared accepts another multi-NUMA-node hint intersecting it",
+			priorRecords: []struct {
+				podUID string
+				hint   TopologyHint
+			}{
+				{"pod-a", hintFor(0, 1)},
+			},
+			candidate:     hintFor(1, 2),
+			expectedAdmit: true,
+		},
+	}
+
+	for _, tcase := range testCases {
+		t.Run(tcase.description, func(t *testing.T) {
+			tracker := newNUMANodeTracker()
+			for _, r := range tcase.priorRecords {
+				tracker.record(r.podUID, r.hint)
+			}
+
+			if got := tracker.canAdmit(tcase.candidate); got != tcase.expectedAdmit {
+				t.Errorf("expected canAdmit=%v, got %v", tcase.expectedAdmit, got)
+			}
+		})
+	}
+}
+
+func TestNUMANodeTrackerRelease(t *testing.T) {
+	tracker := newNUMANodeTracker()
+	tracker.record("pod-a", hintFor(0))
+	if tracker.status(0) != NUMANodeStatusSingle {
+		t.Fatalf("expected node 0 to be Single, got %v", tracker.status(0))
+	}
+
+	tracker.release("pod-a")
+	if tracker.status(0) != NUMANodeStatusIdle {
+		t.Errorf("expected node 0 to be Idle after release, got %v", tracker.status(0))
+	}
+}
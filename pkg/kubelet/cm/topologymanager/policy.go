@@ -0,0 +1,271 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package topologymanager
+
+import (
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/kubernetes/pkg/kubelet/cm/topologymanager/bitmask"
+)
+
+const (
+	// PolicyNone is the name of the policy that does not perform any
+	// topology alignment.
+	PolicyNone = "none"
+	// PolicyBestEffort is the name of the policy that admits every pod, but
+	// merely attempts to align resource allocations on as few NUMA nodes as
+	// possible.
+	PolicyBestEffort = "best-effort"
+	// PolicyRestricted is the name of the policy that rejects admission of
+	// a pod if all providers cannot reach agreement on a preferred
+	// NUMANodeAffinity.
+	PolicyRestricted = "restricted"
+	// PolicySingleNumaNode is the name of the policy that rejects admission
+	// of a pod unless all providers agree on a single NUMA node affinity.
+	PolicySingleNumaNode = "single-numa-node"
+)
+
+// TopologyHint is a struct containing the NUMANodeAffinity for a Container
+type TopologyHint struct {
+	NUMANodeAffinity bitmask.BitMask
+	// Preferred is set to true when the NUMANodeAffinity encodes a
+	// preferred allocation for the Container. It is set to false otherwise.
+	Preferred bool
+}
+
+// IsEqual checks if TopologyHint are equal
+func (th *TopologyHint) IsEqual(topologyHint TopologyHint) bool {
+	if th.NUMANodeAffinity == nil || topologyHint.NUMANodeAffinity == nil {
+		return th.NUMANodeAffinity == topologyHint.NUMANodeAffinity && th.Preferred == topologyHint.Preferred
+	}
+	return th.NUMANodeAffinity.IsEqual(topologyHint.NUMANodeAffinity) && th.Preferred == topologyHint.Preferred
+}
+
+// LessThan checks if TopologyHint `a` is less than TopologyHint `b`. This
+// means that `a` is preferred over `b`.
+func (th *TopologyHint) LessThan(other TopologyHint) bool {
+	if th.Preferred != other.Preferred {
+		return th.Preferred
+	}
+	return th.NUMANodeAffinity.IsNarrowerThan(other.NUMANodeAffinity)
+}
+
+// HintProvider is an interface for components that want to collaborate to
+// achieve globally optimal concrete resource alignment with respect to NUMA
+// locality.
+type HintProvider interface {
+	// GetTopologyHints returns hints for the given pod and container.
+	GetTopologyHints(pod *v1.Pod, container *v1.Container) map[string][]TopologyHint
+	// GetPodTopologyHints returns hints for the given pod, aggregated over
+	// all of its containers.
+	GetPodTopologyHints(pod *v1.Pod) map[string][]TopologyHint
+	// Allocate triggers the allocation of topology aware resources for the
+	// given pod and container once the manager has decided to admit it.
+	Allocate(pod *v1.Pod, container *v1.Container) error
+}
+
+// Policy is an interface for topology manager policy, it defines the
+// functions needed for a policy to be consulted and to merge hints returned
+// from hint providers.
+type Policy interface {
+	// Name returns name of the policy.
+	Name() string
+	// Merge returns a merged TopologyHint based on input from all of the
+	// hint providers, and whether or not the merged hint is admissible for
+	// this policy. tracker records, across all admitted pods, which NUMA
+	// nodes are currently held exclusively; it is consulted when
+	// opts.SingleNUMANodeExclusive is set, and updated via AddContainer for
+	// every admitted container.
+	Merge(opts PolicyOptions, numaInfo *NUMAInfo, providersHints []map[string][]TopologyHint, tracker *numaNodeTracker) (TopologyHint, bool)
+}
+
+// filterProvidersHints flattens the map of hints returned by each hint
+// provider into a slice of per-provider hint slices, substituting a
+// single, trivially satisfiable hint for any provider that returned none.
+func filterProvidersHints(providersHints []map[string][]TopologyHint) [][]TopologyHint {
+	var allProviderHints [][]TopologyHint
+	for _, hints := range providersHints {
+		// If hints is empty, the provider has no preference.
+		if len(hints) == 0 {
+			allProviderHints = append(allProviderHints, []TopologyHint{{nil, true}})
+			continue
+		}
+
+		for resource := range hints {
+			if hints[resource] == nil {
+				allProviderHints = append(allProviderHints, []TopologyHint{{nil, true}})
+				continue
+			}
+
+			if len(hints[resource]) == 0 {
+				allProviderHints = append(allProviderHints, []TopologyHint{{nil, false}})
+				continue
+			}
+
+			allProviderHints = append(allProviderHints, hints[resource])
+		}
+	}
+	return allProviderHints
+}
+
+// filterHintsByAllowedNUMANodes drops any hint whose NUMANodeAffinity
+// references a NUMA node outside of opts.AllowedNUMANodes. When
+// opts.AllowedNUMANodes is empty, every NUMA node is allowed and the input
+// is returned unmodified. A provider's slot is preserved even if every one
+// of its hints gets filtered out, so that mergePermutation still considers
+// (and then rejects) that provider rather than silently dropping it.
+func filterHintsByAllowedNUMANodes(providersHints [][]TopologyHint, allowed []int) [][]TopologyHint {
+	if len(allowed) == 0 {
+		return providersHints
+	}
+
+	allowedMask, err := bitmask.NewBitMask(allowed...)
+	if err != nil {
+		return providersHints
+	}
+
+	var filtered [][]TopologyHint
+	for _, hints := range providersHints {
+		var filteredHints []TopologyHint
+		for _, hint := range hints {
+			if hint.NUMANodeAffinity == nil || isSubsetOf(hint.NUMANodeAffinity, allowedMask) {
+				filteredHints = append(filteredHints, hint)
+			}
+		}
+		if len(filteredHints) == 0 {
+			// Every hint this provider returned fell outside the allow
+			// list. Use the same "no feasible preference" sentinel as
+			// filterProvidersHints, rather than an empty slice: an empty
+			// slice here would make iterateAllProviderTopologyHints skip
+			// this provider's slot entirely, collapsing the whole
+			// permutation cross product instead of just ruling this
+			// provider's hints out of it.
+			filteredHints = []TopologyHint{{nil, false}}
+		}
+		filtered = append(filtered, filteredHints)
+	}
+	return filtered
+}
+
+// isSubsetOf returns true if every bit set in mask is also set in of.
+func isSubsetOf(mask, of bitmask.BitMask) bool {
+	for _, bit := range mask.GetBits() {
+		if !of.IsSet(bit) {
+			return false
+		}
+	}
+	return true
+}
+
+// defaultAffinity returns the bitmask covering every NUMA node the pod is
+// allowed to use: opts.AllowedNUMANodes when set, every NUMA node on the
+// host otherwise. It is used as the starting point for default,
+// no-preference hints, so that scoring never considers a NUMA node the pod
+// is not allowed to use. If opts.AllowedNUMANodes somehow failed to build
+// into a bitmask, this fails closed with an empty mask (allowing no NUMA
+// node) rather than returning nil, so callers never have to special-case a
+// missing affinity.
+func defaultAffinity(numaInfo *NUMAInfo, opts PolicyOptions) bitmask.BitMask {
+	nodes := numaInfo.Nodes
+	if len(opts.AllowedNUMANodes) > 0 {
+		nodes = opts.AllowedNUMANodes
+	}
+	affinity, err := bitmask.NewBitMask(nodes...)
+	if err != nil {
+		return bitmask.NewEmptyBitMask()
+	}
+	return affinity
+}
+
+// mergePermutation merges one hint from each provider (a "permutation" of
+// the cross product of all providers' hints) into a single TopologyHint by
+// ANDing together their NUMANodeAffinity masks. The result is additionally
+// ANDed against opts.AllowedNUMANodes (when set), so a merged hint can never
+// span a NUMA node the pod isn't allowed to use even if every provider
+// happened to agree on one.
+func mergePermutation(numaInfo *NUMAInfo, opts PolicyOptions, permutation []TopologyHint) TopologyHint {
+	allowedAffinity := defaultAffinity(numaInfo, opts)
+
+	preferred := true
+	var numaAffinities []bitmask.BitMask
+	for _, hint := range permutation {
+		if hint.NUMANodeAffinity == nil {
+			numaAffinities = append(numaAffinities, allowedAffinity)
+		} else {
+			numaAffinities = append(numaAffinities, hint.NUMANodeAffinity)
+		}
+
+		if !hint.Preferred {
+			preferred = false
+		}
+	}
+
+	mergedAffinity := bitmask.NewEmptyBitMask()
+	mergedAffinity.Fill()
+	mergedAffinity.And(append(numaAffinities, allowedAffinity)...)
+
+	if opts.PreferClosestNUMA {
+		preferred = preferred && mergedAffinity.IsNarrowerThan(allowedAffinity)
+	}
+
+	if opts.NUMADistanceThreshold > 0 && exceedsNUMADistanceThreshold(numaInfo, mergedAffinity, opts.NUMADistanceThreshold) {
+		preferred = false
+	}
+
+	return TopologyHint{mergedAffinity, preferred}
+}
+
+// numaDistanceCost returns the sum of the pairwise NUMA distances between
+// every pair of NUMA nodes set in mask, as weighted by mode: "linear" sums
+// the raw cadvisor-reported distances, "quadratic" sums their squares. It
+// returns 0 for "" or NUMADistanceWeightNone, and for a mask spanning fewer
+// than two NUMA nodes.
+func numaDistanceCost(numaInfo *NUMAInfo, mask bitmask.BitMask, mode string) uint64 {
+	if mask == nil || mode == "" || mode == NUMADistanceWeightNone {
+		return 0
+	}
+
+	var cost uint64
+	bits := mask.GetBits()
+	for i := 0; i < len(bits); i++ {
+		for j := i + 1; j < len(bits); j++ {
+			d := numaInfo.Distance(bits[i], bits[j])
+			if mode == NUMADistanceWeightQuadratic {
+				d *= d
+			}
+			cost += d
+		}
+	}
+	return cost
+}
+
+// exceedsNUMADistanceThreshold returns true if any pairwise distance between
+// the NUMA nodes set in mask exceeds threshold.
+func exceedsNUMADistanceThreshold(numaInfo *NUMAInfo, mask bitmask.BitMask, threshold int) bool {
+	if mask == nil {
+		return false
+	}
+
+	bits := mask.GetBits()
+	for i := 0; i < len(bits); i++ {
+		for j := i + 1; j < len(bits); j++ {
+			if numaInfo.Distance(bits[i], bits[j]) > uint64(threshold) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,130 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package topologymanager
+
+import (
+	"testing"
+
+	"k8s.io/kubernetes/pkg/kubelet/cm/topologymanager/bitmask"
+)
+
+func newTestNUMAInfo(nodes ...int) *NUMAInfo {
+	return &NUMAInfo{Nodes: nodes}
+}
+
+func TestFilterHintsByAllowedNUMANodes(t *testing.T) {
+	hints := [][]TopologyHint{
+		{hintFor(0), hintFor(2)},
+		{hintFor(1)},
+	}
+
+	filtered := filterHintsByAllowedNUMANodes(hints, []int{0, 1})
+	if len(filtered) != 2 {
+		t.Fatalf("expected 2 provider slots, got %d", len(filtered))
+	}
+	if len(filtered[0]) != 1 || !filtered[0][0].NUMANodeAffinity.IsEqual(hintFor(0).NUMANodeAffinity) {
+		t.Errorf("expected node 2's hint to be filtered out of provider 0, got %+v", filtered[0])
+	}
+	if len(filtered[1]) != 1 {
+		t.Errorf("expected provider 1's hint for allowed node 1 to survive, got %+v", filtered[1])
+	}
+}
+
+func TestFilterHintsByAllowedNUMANodesNoop(t *testing.T) {
+	hints := [][]TopologyHint{{hintFor(0), hintFor(2)}}
+	filtered := filterHintsByAllowedNUMANodes(hints, nil)
+	if len(filtered[0]) != 2 {
+		t.Errorf("expected no filtering when AllowedNUMANodes is empty, got %+v", filtered)
+	}
+}
+
+func TestHintMergerRespectsAllowedNUMANodes(t *testing.T) {
+	numaInfo := newTestNUMAInfo(0, 1, 2, 3)
+
+	providersHints := []map[string][]TopologyHint{
+		{"res-a": {hintFor(2)}},
+	}
+
+	opts := PolicyOptions{MaxAllowableNUMANodes: 8, AllowedNUMANodes: []int{0, 1}}
+	bestHint := NewHintMerger(numaInfo, providersHints, PolicyRestricted, opts).Merge()
+
+	if bestHint.Preferred {
+		t.Fatalf("expected no feasible hint under the allow-list, got preferred hint %+v", bestHint)
+	}
+}
+
+func TestHintMergerIgnoresFullyFilteredProvider(t *testing.T) {
+	numaInfo := newTestNUMAInfo(0, 1)
+
+	providersHints := []map[string][]TopologyHint{
+		// Entirely outside the allow-list: every hint this provider
+		// offers must be filtered out.
+		{"res-disallowed": {hintFor(2)}},
+		// A second, genuinely allowed provider with a narrower hint than
+		// the full allowed set.
+		{"res-allowed": {hintFor(0)}},
+	}
+
+	opts := PolicyOptions{MaxAllowableNUMANodes: 8, AllowedNUMANodes: []int{0, 1}}
+	bestHint := NewHintMerger(numaInfo, providersHints, PolicyBestEffort, opts).Merge()
+
+	if bestHint.NUMANodeAffinity == nil || bestHint.NUMANodeAffinity.Count() != 1 || !bestHint.NUMANodeAffinity.IsEqual(hintFor(0).NUMANodeAffinity) {
+		t.Fatalf("expected the other provider's narrower hint on node 0 to survive a fully-filtered provider, got %+v", bestHint.NUMANodeAffinity)
+	}
+}
+
+func TestHintMergerAllowedNUMANodesFeasible(t *testing.T) {
+	numaInfo := newTestNUMAInfo(0, 1, 2, 3)
+
+	providersHints := []map[string][]TopologyHint{
+		{"res-a": {hintFor(0), hintFor(2)}},
+	}
+
+	opts := PolicyOptions{MaxAllowableNUMANodes: 8, AllowedNUMANodes: []int{0, 1}}
+	bestHint := NewHintMerger(numaInfo, providersHints, PolicyRestricted, opts).Merge()
+
+	if !bestHint.Preferred {
+		t.Fatalf("expected hint on allowed node 0 to be picked, got %+v", bestHint)
+	}
+	if !bestHint.NUMANodeAffinity.IsEqual(hintFor(0).NUMANodeAffinity) {
+		t.Errorf("expected affinity restricted to node 0, got %v", bestHint.NUMANodeAffinity)
+	}
+}
+
+func TestMergePermutationPreferClosestNUMAAmongAllowedNodes(t *testing.T) {
+	numaInfo := newTestNUMAInfo(0, 1, 2, 3)
+	opts := PolicyOptions{PreferClosestNUMA: true, AllowedNUMANodes: []int{0, 1, 2, 3}}
+
+	narrow, err := bitmask.NewBitMask(0, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wide, err := bitmask.NewBitMask(0, 1, 2, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	narrowHint := mergePermutation(numaInfo, opts, []TopologyHint{{NUMANodeAffinity: narrow, Preferred: true}})
+	wideHint := mergePermutation(numaInfo, opts, []TopologyHint{{NUMANodeAffinity: wide, Preferred: true}})
+
+	if !narrowHint.Preferred {
+		t.Errorf("expected the narrower hint among allowed nodes to be preferred")
+	}
+	if wideHint.Preferred {
+		t.Errorf("expected the widest possible hint (equal to defaultAffinity) not to be preferred")
+	}
+}
@@ -0,0 +1,41 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package topologymanager
+
+type bestEffortPolicy struct{}
+
+var _ Policy = &bestEffortPolicy{}
+
+// NewBestEffortPolicy returns a policy that attempts to align resources on
+// as few NUMA nodes as possible, but admits the pod regardless of whether
+// that attempt succeeds.
+func NewBestEffortPolicy() Policy {
+	return &bestEffortPolicy{}
+}
+
+func (p *bestEffortPolicy) Name() string {
+	return PolicyBestEffort
+}
+
+func (p *bestEffortPolicy) Merge(opts PolicyOptions, numaInfo *NUMAInfo, providersHints []map[string][]TopologyHint, tracker *numaNodeTracker) (TopologyHint, bool) {
+	hint := NewHintMerger(numaInfo, providersHints, p.Name(), opts).Merge()
+	return hint, p.canAdmitPodResult(&hint)
+}
+
+func (p *bestEffortPolicy) canAdmitPodResult(hint *TopologyHint) bool {
+	return true
+}
@@ -0,0 +1,201 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package topologymanager
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	utilfeature "k8s.io/apiserver/pkg/util/feature"
+	featuregatetesting "k8s.io/component-base/featuregate/testing"
+	pkgfeatures "k8s.io/kubernetes/pkg/features"
+)
+
+func TestNewTopologyManagerOptionsNUMADistanceWeight(t *testing.T) {
+	testCases := []struct {
+		description       string
+		policyOptions     map[string]string
+		featureGateEnable bool
+		expectedErr       error
+		expectedOptions   PolicyOptions
+	}{
+		{
+			description:       "return TopologyManagerOptions with NUMADistanceWeight set to linear",
+			featureGateEnable: true,
+			policyOptions: map[string]string{
+				NUMADistanceWeight: "linear",
+			},
+			expectedOptions: PolicyOptions{
+				MaxAllowableNUMANodes: 8,
+				NUMADistanceWeight:    "linear",
+			},
+		},
+		{
+			description:       "return TopologyManagerOptions with NUMADistanceWeight set to quadratic and a threshold",
+			featureGateEnable: true,
+			policyOptions: map[string]string{
+				NUMADistanceWeight:    "quadratic",
+				NUMADistanceThreshold: "20",
+			},
+			expectedOptions: PolicyOptions{
+				MaxAllowableNUMANodes: 8,
+				NUMADistanceWeight:    "quadratic",
+				NUMADistanceThreshold: 20,
+			},
+		},
+		{
+			description: "fail to set NUMADistanceWeight when TopologyManagerPolicyBetaOptions feature gate is not set",
+			policyOptions: map[string]string{
+				NUMADistanceWeight: "linear",
+			},
+			expectedErr: fmt.Errorf("topology manager policy beta-level options not enabled,"),
+		},
+		{
+			description:       "fail to parse NUMADistanceWeight with an unknown mode",
+			featureGateEnable: true,
+			policyOptions: map[string]string{
+				NUMADistanceWeight: "exponential",
+			},
+			expectedErr: fmt.Errorf("bad value for option"),
+		},
+		{
+			description:       "fail to parse NUMADistanceThreshold with a non-integer value",
+			featureGateEnable: true,
+			policyOptions: map[string]string{
+				NUMADistanceThreshold: "not a number",
+			},
+			expectedErr: fmt.Errorf("unable to convert policy option to integer"),
+		},
+	}
+
+	for _, tcase := range testCases {
+		t.Run(tcase.description, func(t *testing.T) {
+			featuregatetesting.SetFeatureGateDuringTest(t, utilfeature.DefaultFeatureGate, pkgfeatures.TopologyManagerPolicyBetaOptions, tcase.featureGateEnable)
+
+			opts, err := NewPolicyOptions(tcase.policyOptions)
+			if tcase.expectedErr != nil {
+				if err == nil || !strings.Contains(err.Error(), tcase.expectedErr.Error()) {
+					t.Errorf("Unexpected error. Have: %v, wants %s", err, tcase.expectedErr.Error())
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if opts.NUMADistanceWeight != tcase.expectedOptions.NUMADistanceWeight {
+				t.Errorf("Expected NUMADistanceWeight to equal %q, not %q", tcase.expectedOptions.NUMADistanceWeight, opts.NUMADistanceWeight)
+			}
+			if opts.NUMADistanceThreshold != tcase.expectedOptions.NUMADistanceThreshold {
+				t.Errorf("Expected NUMADistanceThreshold to equal %d, not %d", tcase.expectedOptions.NUMADistanceThreshold, opts.NUMADistanceThreshold)
+			}
+		})
+	}
+}
+
+// asymmetricNUMAInfo returns a 4-node NUMAInfo whose nodes form two uneven
+// pairs: nodes 0 and 1 are at distance 15, nodes 2 and 3 at distance 11, and
+// every node is at distance 20 from both nodes of the other pair. Linear and
+// quadratic weighting agree on this topology; see
+// threeNodeDisagreementNUMAInfo for one where they do not.
+func asymmetricNUMAInfo() *NUMAInfo {
+	return &NUMAInfo{
+		Nodes: []int{0, 1, 2, 3},
+		NUMADistances: map[int][]int{
+			0: {10, 15, 20, 20},
+			1: {15, 10, 20, 20},
+			2: {20, 20, 10, 11},
+			3: {20, 20, 11, 10},
+		},
+	}
+}
+
+// threeNodeDisagreementNUMAInfo returns a 4-node NUMAInfo whose distance
+// matrix is built so that two equally-sized, 3-node candidate hints —
+// {0,1,2} and {0,1,3} — are ranked oppositely by the linear and quadratic
+// cost functions. {0,1,2}'s pairwise distances are spread (1 and 10) so
+// squaring them dominates its sum, while {0,1,3}'s are uniform (6 and 6),
+// giving it a larger linear sum but a smaller quadratic one:
+//
+//	{0,1,2}: linear 10+1+10 = 21,  quadratic 100+1+100  = 201
+//	{0,1,3}: linear 10+6+6  = 22,  quadratic 100+36+36  = 172
+//
+// so linear prefers {0,1,2} (21 < 22) while quadratic prefers {0,1,3}
+// (172 < 201).
+func threeNodeDisagreementNUMAInfo() *NUMAInfo {
+	return &NUMAInfo{
+		Nodes: []int{0, 1, 2, 3},
+		NUMADistances: map[int][]int{
+			0: {10, 10, 1, 6},
+			1: {10, 10, 10, 6},
+			2: {1, 10, 10, 10},
+			3: {6, 6, 10, 10},
+		},
+	}
+}
+
+func TestHintMergerNUMADistanceWeight(t *testing.T) {
+	numaInfo := threeNodeDisagreementNUMAInfo()
+
+	providersHints := []map[string][]TopologyHint{
+		{"res-a": {hintFor(0, 1, 2), hintFor(0, 1, 3)}},
+	}
+
+	linearOpts := PolicyOptions{MaxAllowableNUMANodes: 8, NUMADistanceWeight: NUMADistanceWeightLinear}
+	linearHint := NewHintMerger(numaInfo, providersHints, PolicyBestEffort, linearOpts).Merge()
+	if !linearHint.NUMANodeAffinity.IsEqual(hintFor(0, 1, 2).NUMANodeAffinity) {
+		t.Errorf("expected linear weighting to pick the (0,1,2) hint (cost 21), got %v", linearHint.NUMANodeAffinity)
+	}
+
+	quadraticOpts := PolicyOptions{MaxAllowableNUMANodes: 8, NUMADistanceWeight: NUMADistanceWeightQuadratic}
+	quadraticHint := NewHintMerger(numaInfo, providersHints, PolicyBestEffort, quadraticOpts).Merge()
+	if !quadraticHint.NUMANodeAffinity.IsEqual(hintFor(0, 1, 3).NUMANodeAffinity) {
+		t.Errorf("expected quadratic weighting to pick the (0,1,3) hint (cost 172), got %v", quadraticHint.NUMANodeAffinity)
+	}
+}
+
+func TestHintMergerNUMADistanceThreshold(t *testing.T) {
+	numaInfo := asymmetricNUMAInfo()
+
+	providersHints := []map[string][]TopologyHint{
+		{"res-a": {hintFor(0, 1)}},
+	}
+
+	opts := PolicyOptions{MaxAllowableNUMANodes: 8, NUMADistanceThreshold: 10}
+	bestHint := NewHintMerger(numaInfo, providersHints, PolicyRestricted, opts).Merge()
+
+	if bestHint.Preferred {
+		t.Fatalf("expected hint spanning a distance-15 pair to be rejected by a threshold of 10, got %+v", bestHint)
+	}
+}
+
+func TestNUMADistanceCost(t *testing.T) {
+	numaInfo := asymmetricNUMAInfo()
+
+	mask := hintFor(2, 3).NUMANodeAffinity
+
+	if cost := numaDistanceCost(numaInfo, mask, NUMADistanceWeightNone); cost != 0 {
+		t.Errorf("expected NUMADistanceWeightNone to cost 0, got %d", cost)
+	}
+	if cost := numaDistanceCost(numaInfo, mask, NUMADistanceWeightLinear); cost != 11 {
+		t.Errorf("expected linear cost of 11, got %d", cost)
+	}
+	if cost := numaDistanceCost(numaInfo, mask, NUMADistanceWeightQuadratic); cost != 121 {
+		t.Errorf("expected quadratic cost of 121, got %d", cost)
+	}
+}
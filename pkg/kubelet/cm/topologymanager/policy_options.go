@@ -0,0 +1,465 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package topologymanager
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+	utilfeature "k8s.io/apiserver/pkg/util/feature"
+	pkgfeatures "k8s.io/kubernetes/pkg/features"
+	kubeletconfig "k8s.io/kubernetes/pkg/kubelet/apis/config"
+)
+
+const (
+	// PreferClosestNUMANodes is the name of the option that makes the
+	// topology manager favor sets of NUMA nodes with shorter distance
+	// between them when more than one with the same number of NUMA nodes
+	// has been found.
+	PreferClosestNUMANodes = "prefer-closest-numa-nodes"
+
+	// MaxAllowableNUMANodes is the name of the option that sets the maximum
+	// number of NUMA nodes allowed in the merged hint that a policy will
+	// admit. Evaluating all permutations of NUMA nodes and generating their
+	// affinity masks grows factorially with the number of NUMA nodes, so
+	// this keeps the admission cost bounded on large machines.
+	MaxAllowableNUMANodes = "max-allowable-numa-nodes"
+
+	// AllowedNUMANodes is the name of the option that restricts topology
+	// hint generation and merging to the given, comma-separated list of
+	// NUMA node IDs.
+	AllowedNUMANodes = "allowed-numa-nodes"
+
+	// SingleNUMANodeExclusive is the name of the option that, once a pod
+	// has been granted a single-NUMA-node hint, prevents that NUMA node
+	// from also being used by any pod admitted with a multi-NUMA-node
+	// hint (and vice versa). The check is made by the restricted and
+	// single-numa-node policies, when admitting a pod whose options set it.
+	SingleNUMANodeExclusive = "single-numa-node-exclusive"
+
+	// NUMADistanceWeight is the name of the option that replaces the
+	// boolean PreferClosestNUMANodes tie-break with a distance cost
+	// function. Its value must be one of the NUMADistanceWeight* values
+	// below.
+	NUMADistanceWeight = "numa-distance-weight"
+
+	// NUMADistanceWeightNone disables distance-weighted scoring.
+	NUMADistanceWeightNone = "none"
+	// NUMADistanceWeightLinear scores a candidate hint by the sum of the
+	// pairwise distances between the NUMA nodes it spans.
+	NUMADistanceWeightLinear = "linear"
+	// NUMADistanceWeightQuadratic scores a candidate hint by the sum of
+	// the squares of the pairwise distances between the NUMA nodes it
+	// spans, penalizing wide hints more aggressively than linear.
+	NUMADistanceWeightQuadratic = "quadratic"
+
+	// NUMADistanceThreshold is the name of the option that, when set to a
+	// positive integer, marks a candidate hint as non-preferred if any
+	// pairwise distance between the NUMA nodes it spans exceeds it.
+	NUMADistanceThreshold = "numa-distance-threshold"
+
+	// defaultMaxAllowableNUMANodes is used unless MaxAllowableNUMANodes is
+	// explicitly set.
+	defaultMaxAllowableNUMANodes = 8
+)
+
+// numaDistanceWeightModes is the set of values accepted for NUMADistanceWeight.
+var numaDistanceWeightModes = sets.New[string](
+	NUMADistanceWeightNone,
+	NUMADistanceWeightLinear,
+	NUMADistanceWeightQuadratic,
+)
+
+var (
+	// alphaOptions holds the names of options that require the
+	// TopologyManagerPolicyAlphaOptions feature gate to be enabled.
+	alphaOptions = sets.New[string](
+		SingleNUMANodeExclusive,
+	)
+
+	// betaOptions holds the names of options that require the
+	// TopologyManagerPolicyBetaOptions feature gate to be enabled.
+	betaOptions = sets.New[string](
+		MaxAllowableNUMANodes,
+		NUMADistanceWeight,
+		NUMADistanceThreshold,
+	)
+
+	// stableOptions holds the names of options that are always available,
+	// regardless of feature gate state.
+	stableOptions = sets.New[string](
+		PreferClosestNUMANodes,
+		AllowedNUMANodes,
+	)
+)
+
+// PolicyOptions holds the parsed value of the `--topology-manager-policy-options`
+// kubelet flag (or, for a single pod, the merged result of that flag with
+// the pod's per-pod override, see PodTopologySpec below).
+type PolicyOptions struct {
+	// PreferClosestNUMA affects the minimal policies (best-effort,
+	// restricted) by requesting the smallest hint, among the ones with
+	// the fewest NUMA nodes, be preferred.
+	PreferClosestNUMA bool
+	// MaxAllowableNUMANodes bounds how many NUMA nodes a merged hint may
+	// span before a policy gives up on it.
+	MaxAllowableNUMANodes int
+	// AllowedNUMANodes restricts hint generation and merging to this set
+	// of NUMA node IDs. An empty slice means every NUMA node is allowed.
+	AllowedNUMANodes []int
+	// SingleNUMANodeExclusive requests that, once this pod is granted a
+	// single-NUMA-node hint, the NUMA node it lands on is not shared with
+	// any pod admitted with a multi-NUMA-node hint (and vice versa). Only
+	// the restricted and single-numa-node policies enforce it, and only for
+	// a pod whose PolicyOptions set it.
+	SingleNUMANodeExclusive bool
+	// NUMADistanceWeight selects how the HintMerger scores candidate hints
+	// that tie on preference and NUMA node count: "" or "none" keeps the
+	// legacy narrowest-affinity tie-break, "linear"/"quadratic" instead
+	// minimize a pairwise NUMA distance cost function.
+	NUMADistanceWeight string
+	// NUMADistanceThreshold, when positive, marks a candidate hint as
+	// non-preferred if any pairwise distance between the NUMA nodes it
+	// spans exceeds it.
+	NUMADistanceThreshold int
+}
+
+// NewPolicyOptions validates the given string options and converts them to
+// a PolicyOptions struct. It parses each string into the corresponding
+// field of a TopologyManagerPolicyOptionsConfig and delegates to
+// NewPolicyOptionsFromConfig, so the two configuration sources share a
+// single validation path.
+func NewPolicyOptions(policyOptions map[string]string) (PolicyOptions, error) {
+	cfg := &kubeletconfig.TopologyManagerPolicyOptionsConfig{}
+	var unknown []string
+
+	for name, value := range policyOptions {
+		switch name {
+		case PreferClosestNUMANodes:
+			boolValue, err := strconv.ParseBool(value)
+			if err != nil {
+				return PolicyOptions{}, fmt.Errorf("bad value for option %q: %w", name, err)
+			}
+			cfg.PreferClosestNUMANodes = boolValue
+		case MaxAllowableNUMANodes:
+			uintValue, err := strconv.ParseUint(value, 10, 32)
+			if err != nil {
+				return PolicyOptions{}, fmt.Errorf("unable to convert policy option to integer: %w", err)
+			}
+			cfg.MaxAllowableNUMANodes = int(uintValue)
+		case AllowedNUMANodes:
+			nodes, err := parseAllowedNUMANodes(value)
+			if err != nil {
+				return PolicyOptions{}, err
+			}
+			cfg.AllowedNUMANodes = nodes
+		case SingleNUMANodeExclusive:
+			boolValue, err := strconv.ParseBool(value)
+			if err != nil {
+				return PolicyOptions{}, fmt.Errorf("bad value for option %q: %w", name, err)
+			}
+			cfg.SingleNUMANodeExclusive = boolValue
+		case NUMADistanceWeight:
+			cfg.NUMADistanceWeight = value
+		case NUMADistanceThreshold:
+			uintValue, err := strconv.ParseUint(value, 10, 32)
+			if err != nil {
+				return PolicyOptions{}, fmt.Errorf("unable to convert policy option to integer: %w", err)
+			}
+			cfg.NUMADistanceThreshold = int(uintValue)
+		default:
+			// Not one of the options this binary knows how to parse into
+			// TopologyManagerPolicyOptionsConfig (it may be a fancy
+			// test-only option, or a future option). Still run it through
+			// CheckPolicyOptionAvailable below so unknown/disabled options
+			// are rejected exactly as before.
+			unknown = append(unknown, name)
+		}
+	}
+
+	opts, err := NewPolicyOptionsFromConfig(cfg)
+	if err != nil {
+		return PolicyOptions{}, err
+	}
+
+	for _, name := range unknown {
+		if err := CheckPolicyOptionAvailable(name); err != nil {
+			return PolicyOptions{}, err
+		}
+	}
+
+	return opts, nil
+}
+
+// NewPolicyOptionsFromConfig validates cfg and converts it to a
+// PolicyOptions struct. Every field cfg sets to a non-zero value is checked
+// against the same alpha/beta/stable gating tables used by NewPolicyOptions,
+// so the typed and string-map configuration sources share one source of
+// truth for option validation and availability.
+func NewPolicyOptionsFromConfig(cfg *kubeletconfig.TopologyManagerPolicyOptionsConfig) (PolicyOptions, error) {
+	opts := PolicyOptions{
+		MaxAllowableNUMANodes: defaultMaxAllowableNUMANodes,
+	}
+	if cfg == nil {
+		return opts, nil
+	}
+
+	if cfg.PreferClosestNUMANodes {
+		if err := CheckPolicyOptionAvailable(PreferClosestNUMANodes); err != nil {
+			return PolicyOptions{}, err
+		}
+		opts.PreferClosestNUMA = true
+	}
+
+	if cfg.MaxAllowableNUMANodes != 0 {
+		if err := CheckPolicyOptionAvailable(MaxAllowableNUMANodes); err != nil {
+			return PolicyOptions{}, err
+		}
+		if cfg.MaxAllowableNUMANodes < 0 {
+			return PolicyOptions{}, fmt.Errorf("bad value for option %q: must be non-negative, got %d", MaxAllowableNUMANodes, cfg.MaxAllowableNUMANodes)
+		}
+		opts.MaxAllowableNUMANodes = cfg.MaxAllowableNUMANodes
+	}
+
+	if len(cfg.AllowedNUMANodes) > 0 {
+		if err := CheckPolicyOptionAvailable(AllowedNUMANodes); err != nil {
+			return PolicyOptions{}, err
+		}
+		nodes, err := validateAllowedNUMANodes(cfg.AllowedNUMANodes)
+		if err != nil {
+			return PolicyOptions{}, err
+		}
+		opts.AllowedNUMANodes = nodes
+	}
+
+	if cfg.SingleNUMANodeExclusive {
+		if err := CheckPolicyOptionAvailable(SingleNUMANodeExclusive); err != nil {
+			return PolicyOptions{}, err
+		}
+		opts.SingleNUMANodeExclusive = true
+	}
+
+	if cfg.NUMADistanceWeight != "" {
+		if err := CheckPolicyOptionAvailable(NUMADistanceWeight); err != nil {
+			return PolicyOptions{}, err
+		}
+		if !numaDistanceWeightModes.Has(cfg.NUMADistanceWeight) {
+			return PolicyOptions{}, fmt.Errorf("bad value for option %q: must be one of %v, got %q", NUMADistanceWeight, sets.List(numaDistanceWeightModes), cfg.NUMADistanceWeight)
+		}
+		opts.NUMADistanceWeight = cfg.NUMADistanceWeight
+	}
+
+	if cfg.NUMADistanceThreshold != 0 {
+		if err := CheckPolicyOptionAvailable(NUMADistanceThreshold); err != nil {
+			return PolicyOptions{}, err
+		}
+		if cfg.NUMADistanceThreshold < 0 {
+			return PolicyOptions{}, fmt.Errorf("bad value for option %q: must be non-negative, got %d", NUMADistanceThreshold, cfg.NUMADistanceThreshold)
+		}
+		opts.NUMADistanceThreshold = cfg.NUMADistanceThreshold
+	}
+
+	return opts, nil
+}
+
+// parseAllowedNUMANodes parses a comma-separated list of NUMA node IDs from
+// the string-map configuration source, then validates it via
+// validateAllowedNUMANodes.
+func parseAllowedNUMANodes(value string) ([]int, error) {
+	if strings.TrimSpace(value) == "" {
+		return nil, fmt.Errorf("empty value for option %q", AllowedNUMANodes)
+	}
+
+	var nodes []int
+	for _, tok := range strings.Split(value, ",") {
+		id, err := strconv.Atoi(strings.TrimSpace(tok))
+		if err != nil {
+			return nil, fmt.Errorf("invalid NUMA node ID %q in option %q: %w", tok, AllowedNUMANodes, err)
+		}
+		nodes = append(nodes, id)
+	}
+	return validateAllowedNUMANodes(nodes)
+}
+
+// validateAllowedNUMANodes checks that nodes is non-empty and every NUMA
+// node ID in it is non-negative and unique, returning nodes unmodified if
+// so. It is the single validation path shared by the string-map
+// (parseAllowedNUMANodes), typed (NewPolicyOptionsFromConfig), and per-pod
+// annotation (MergePodPolicyOptions) configuration sources.
+func validateAllowedNUMANodes(nodes []int) ([]int, error) {
+	if len(nodes) == 0 {
+		return nil, fmt.Errorf("empty value for option %q", AllowedNUMANodes)
+	}
+
+	seen := sets.New[int]()
+	for _, id := range nodes {
+		if id < 0 {
+			return nil, fmt.Errorf("NUMA node ID must be non-negative, got %d", id)
+		}
+		if seen.Has(id) {
+			return nil, fmt.Errorf("duplicate NUMA node ID %d in option %q", id, AllowedNUMANodes)
+		}
+		seen.Insert(id)
+	}
+	return nodes, nil
+}
+
+// CheckPolicyOptionAvailable returns nil if the given option name is known
+// and, for alpha/beta options, enabled by the corresponding feature gate.
+func CheckPolicyOptionAvailable(option string) error {
+	if stableOptions.Has(option) {
+		return nil
+	}
+
+	if !alphaOptions.Has(option) && !betaOptions.Has(option) {
+		return fmt.Errorf("unknown Topology Manager Policy option: %q", option)
+	}
+
+	if alphaOptions.Has(option) && !utilfeature.DefaultFeatureGate.Enabled(pkgfeatures.TopologyManagerPolicyAlphaOptions) {
+		return fmt.Errorf("topology manager policy alpha-level options not enabled, but option %q requires it", option)
+	}
+
+	if betaOptions.Has(option) && !utilfeature.DefaultFeatureGate.Enabled(pkgfeatures.TopologyManagerPolicyBetaOptions) {
+		return fmt.Errorf("topology manager policy beta-level options not enabled, but option %q requires it", option)
+	}
+
+	return nil
+}
+
+// PodTopologyManagerPolicyAnnotation is the pod annotation key that lets a
+// pod request topology manager semantics independent of the kubelet-wide
+// policy/scope/policy-options, gated behind the TopologyManagerPerPodPolicy
+// feature gate. Its value is the JSON encoding of a PodTopologySpec.
+const PodTopologyManagerPolicyAnnotation = "topologymanager.kubelet.kubernetes.io/topology-spec"
+
+// Valid values for PodTopologySpec.Scope.
+const (
+	// TopologyScopeContainer merges hints independently for each container
+	// in the pod; this is the manager's default behavior.
+	TopologyScopeContainer = "container"
+	// TopologyScopePod merges hints once across every container in the
+	// pod and assigns the resulting affinity to all of them, so no
+	// container ends up pinned to a NUMA node another container in the
+	// same pod was not also granted.
+	TopologyScopePod = "pod"
+)
+
+// PodTopologySpec is the annotation payload a pod can use to override the
+// kubelet-wide topology manager policy and options for itself. Any field
+// left at its zero value falls back to the kubelet-wide default.
+type PodTopologySpec struct {
+	// Policy selects the topology manager policy (e.g. "restricted",
+	// "single-numa-node", "best-effort", "none") used to admit this pod.
+	Policy string `json:"policy,omitempty"`
+	// Scope selects the topology manager scope (TopologyScopeContainer or
+	// TopologyScopePod) used to admit this pod.
+	Scope string `json:"scope,omitempty"`
+	// PreferClosestNUMA overrides PolicyOptions.PreferClosestNUMA for this
+	// pod.
+	PreferClosestNUMA bool `json:"preferClosestNUMA,omitempty"`
+	// MaxAllowableNUMANodes overrides PolicyOptions.MaxAllowableNUMANodes
+	// for this pod. Zero means "use the kubelet default".
+	MaxAllowableNUMANodes int `json:"maxAllowableNUMANodes,omitempty"`
+	// AllowedNUMANodes overrides PolicyOptions.AllowedNUMANodes for this
+	// pod.
+	AllowedNUMANodes []int `json:"allowedNUMANodes,omitempty"`
+	// SingleNUMANodeExclusive requests exclusive use of the NUMA node(s)
+	// assigned to this pod; see PolicyOptions.SingleNUMANodeExclusive.
+	SingleNUMANodeExclusive bool `json:"singleNUMANodeExclusive,omitempty"`
+}
+
+// PodTopologySpecFromAnnotations extracts and validates a per-pod topology
+// override from the pod's annotations. It returns a nil spec (and a nil
+// error) when the pod carries no such annotation. The annotation is only
+// honored when the TopologyManagerPerPodPolicy alpha feature gate is
+// enabled; otherwise a pod that sets it is rejected outright, rather than
+// silently falling back to the kubelet-wide policy.
+func PodTopologySpecFromAnnotations(annotations map[string]string) (*PodTopologySpec, error) {
+	raw, ok := annotations[PodTopologyManagerPolicyAnnotation]
+	if !ok {
+		return nil, nil
+	}
+
+	if !utilfeature.DefaultFeatureGate.Enabled(pkgfeatures.TopologyManagerPerPodPolicy) {
+		return nil, fmt.Errorf("pod annotation %q requires the TopologyManagerPerPodPolicy feature gate to be enabled", PodTopologyManagerPolicyAnnotation)
+	}
+
+	spec := &PodTopologySpec{}
+	if err := json.Unmarshal([]byte(raw), spec); err != nil {
+		return nil, fmt.Errorf("unable to parse pod annotation %q: %w", PodTopologyManagerPolicyAnnotation, err)
+	}
+
+	switch spec.Policy {
+	case "", PolicyNone, PolicyBestEffort, PolicyRestricted, PolicySingleNumaNode:
+	default:
+		return nil, fmt.Errorf("pod annotation %q requested unknown policy %q", PodTopologyManagerPolicyAnnotation, spec.Policy)
+	}
+
+	switch spec.Scope {
+	case "", TopologyScopeContainer, TopologyScopePod:
+	default:
+		return nil, fmt.Errorf("pod annotation %q requested unknown scope %q", PodTopologyManagerPolicyAnnotation, spec.Scope)
+	}
+
+	return spec, nil
+}
+
+// MergePodPolicyOptions starts from the kubelet-wide PolicyOptions and
+// applies any field the pod overrode through its PodTopologySpec. Every
+// overridden option is re-validated against the same alpha/beta tables
+// used for the kubelet-wide flags, so a pod cannot use its annotation to
+// reach an option the cluster operator has not enabled.
+func MergePodPolicyOptions(defaults PolicyOptions, spec *PodTopologySpec) (PolicyOptions, error) {
+	merged := defaults
+	if spec == nil {
+		return merged, nil
+	}
+
+	if spec.PreferClosestNUMA {
+		if err := CheckPolicyOptionAvailable(PreferClosestNUMANodes); err != nil {
+			return PolicyOptions{}, err
+		}
+		merged.PreferClosestNUMA = true
+	}
+	if spec.MaxAllowableNUMANodes != 0 {
+		if err := CheckPolicyOptionAvailable(MaxAllowableNUMANodes); err != nil {
+			return PolicyOptions{}, err
+		}
+		merged.MaxAllowableNUMANodes = spec.MaxAllowableNUMANodes
+	}
+	if len(spec.AllowedNUMANodes) > 0 {
+		if err := CheckPolicyOptionAvailable(AllowedNUMANodes); err != nil {
+			return PolicyOptions{}, err
+		}
+		nodes, err := validateAllowedNUMANodes(spec.AllowedNUMANodes)
+		if err != nil {
+			return PolicyOptions{}, err
+		}
+		merged.AllowedNUMANodes = nodes
+	}
+	if spec.SingleNUMANodeExclusive {
+		if err := CheckPolicyOptionAvailable(SingleNUMANodeExclusive); err != nil {
+			return PolicyOptions{}, err
+		}
+		merged.SingleNUMANodeExclusive = true
+	}
+
+	return merged, nil
+}
@@ -0,0 +1,149 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package topologymanager
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	utilfeature "k8s.io/apiserver/pkg/util/feature"
+	featuregatetesting "k8s.io/component-base/featuregate/testing"
+	pkgfeatures "k8s.io/kubernetes/pkg/features"
+	kubeletconfig "k8s.io/kubernetes/pkg/kubelet/apis/config"
+)
+
+func TestNewPolicyOptionsFromConfig(t *testing.T) {
+	testCases := []struct {
+		description       string
+		cfg               *kubeletconfig.TopologyManagerPolicyOptionsConfig
+		featureGateEnable bool
+		expectedErr       error
+		expectedOptions   PolicyOptions
+	}{
+		{
+			description: "nil config returns the defaults",
+			cfg:         nil,
+			expectedOptions: PolicyOptions{
+				MaxAllowableNUMANodes: 8,
+			},
+		},
+		{
+			description: "return PolicyOptions with AllowedNUMANodes set from a typed list",
+			cfg: &kubeletconfig.TopologyManagerPolicyOptionsConfig{
+				AllowedNUMANodes: []int{0, 2, 4, 6},
+			},
+			expectedOptions: PolicyOptions{
+				MaxAllowableNUMANodes: 8,
+				AllowedNUMANodes:      []int{0, 2, 4, 6},
+			},
+		},
+		{
+			description: "fail on a duplicate NUMA node ID",
+			cfg: &kubeletconfig.TopologyManagerPolicyOptionsConfig{
+				AllowedNUMANodes: []int{0, 1, 0},
+			},
+			expectedErr: fmt.Errorf("duplicate NUMA node ID"),
+		},
+		{
+			description: "fail on a negative NUMA node ID",
+			cfg: &kubeletconfig.TopologyManagerPolicyOptionsConfig{
+				AllowedNUMANodes: []int{0, -1},
+			},
+			expectedErr: fmt.Errorf("NUMA node ID must be non-negative"),
+		},
+		{
+			description:       "return PolicyOptions with MaxAllowableNUMANodes set to 12",
+			featureGateEnable: true,
+			cfg: &kubeletconfig.TopologyManagerPolicyOptionsConfig{
+				MaxAllowableNUMANodes: 12,
+			},
+			expectedOptions: PolicyOptions{
+				MaxAllowableNUMANodes: 12,
+			},
+		},
+		{
+			description: "fail to set MaxAllowableNUMANodes when TopologyManagerPolicyBetaOptions feature gate is not set",
+			cfg: &kubeletconfig.TopologyManagerPolicyOptionsConfig{
+				MaxAllowableNUMANodes: 12,
+			},
+			expectedErr: fmt.Errorf("topology manager policy beta-level options not enabled,"),
+		},
+		{
+			description: "fail on a negative MaxAllowableNUMANodes",
+			cfg: &kubeletconfig.TopologyManagerPolicyOptionsConfig{
+				MaxAllowableNUMANodes: -1,
+			},
+			featureGateEnable: true,
+			expectedErr:       fmt.Errorf("bad value for option"),
+		},
+		{
+			description:       "return PolicyOptions with NUMADistanceWeight set to linear",
+			featureGateEnable: true,
+			cfg: &kubeletconfig.TopologyManagerPolicyOptionsConfig{
+				NUMADistanceWeight: "linear",
+			},
+			expectedOptions: PolicyOptions{
+				MaxAllowableNUMANodes: 8,
+				NUMADistanceWeight:    "linear",
+			},
+		},
+		{
+			description:       "fail on an unknown NUMADistanceWeight mode",
+			featureGateEnable: true,
+			cfg: &kubeletconfig.TopologyManagerPolicyOptionsConfig{
+				NUMADistanceWeight: "exponential",
+			},
+			expectedErr: fmt.Errorf("bad value for option"),
+		},
+		{
+			description: "fail on a negative NUMADistanceThreshold",
+			cfg: &kubeletconfig.TopologyManagerPolicyOptionsConfig{
+				NUMADistanceThreshold: -5,
+			},
+			featureGateEnable: true,
+			expectedErr:       fmt.Errorf("bad value for option"),
+		},
+	}
+
+	for _, tcase := range testCases {
+		t.Run(tcase.description, func(t *testing.T) {
+			featuregatetesting.SetFeatureGateDuringTest(t, utilfeature.DefaultFeatureGate, pkgfeatures.TopologyManagerPolicyBetaOptions, tcase.featureGateEnable)
+
+			opts, err := NewPolicyOptionsFromConfig(tcase.cfg)
+			if tcase.expectedErr != nil {
+				if err == nil || !strings.Contains(err.Error(), tcase.expectedErr.Error()) {
+					t.Errorf("Unexpected error. Have: %v, wants %s", err, tcase.expectedErr.Error())
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if opts.MaxAllowableNUMANodes != tcase.expectedOptions.MaxAllowableNUMANodes {
+				t.Errorf("Expected MaxAllowableNUMANodes to equal %v, not %v", tcase.expectedOptions.MaxAllowableNUMANodes, opts.MaxAllowableNUMANodes)
+			}
+			if !intSlicesEqual(opts.AllowedNUMANodes, tcase.expectedOptions.AllowedNUMANodes) {
+				t.Errorf("Expected AllowedNUMANodes to equal %v, not %v", tcase.expectedOptions.AllowedNUMANodes, opts.AllowedNUMANodes)
+			}
+			if opts.NUMADistanceWeight != tcase.expectedOptions.NUMADistanceWeight {
+				t.Errorf("Expected NUMADistanceWeight to equal %q, not %q", tcase.expectedOptions.NUMADistanceWeight, opts.NUMADistanceWeight)
+			}
+		})
+	}
+}
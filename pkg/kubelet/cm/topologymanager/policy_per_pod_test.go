@@ -0,0 +1,207 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package topologymanager
+
+import (
+	"strings"
+	"testing"
+
+	utilfeature "k8s.io/apiserver/pkg/util/feature"
+	"k8s.io/component-base/featuregate"
+	featuregatetesting "k8s.io/component-base/featuregate/testing"
+	pkgfeatures "k8s.io/kubernetes/pkg/features"
+)
+
+func TestPodTopologySpecFromAnnotations(t *testing.T) {
+	testCases := []struct {
+		description       string
+		annotations       map[string]string
+		featureGateEnable bool
+		expectedSpec      *PodTopologySpec
+		expectedErr       string
+	}{
+		{
+			description:  "no annotation present",
+			annotations:  map[string]string{},
+			expectedSpec: nil,
+		},
+		{
+			description: "annotation present but feature gate disabled",
+			annotations: map[string]string{
+				PodTopologyManagerPolicyAnnotation: `{"policy":"restricted"}`,
+			},
+			featureGateEnable: false,
+			expectedErr:       "TopologyManagerPerPodPolicy feature gate to be enabled",
+		},
+		{
+			description: "well formed annotation parses successfully",
+			annotations: map[string]string{
+				PodTopologyManagerPolicyAnnotation: `{"policy":"single-numa-node","scope":"pod","preferClosestNUMA":true,"maxAllowableNUMANodes":4,"allowedNUMANodes":[0,1]}`,
+			},
+			featureGateEnable: true,
+			expectedSpec: &PodTopologySpec{
+				Policy:                PolicySingleNumaNode,
+				Scope:                 TopologyScopePod,
+				PreferClosestNUMA:     true,
+				MaxAllowableNUMANodes: 4,
+				AllowedNUMANodes:      []int{0, 1},
+			},
+		},
+		{
+			description: "unknown scope is rejected",
+			annotations: map[string]string{
+				PodTopologyManagerPolicyAnnotation: `{"scope":"made-up-scope"}`,
+			},
+			featureGateEnable: true,
+			expectedErr:       "requested unknown scope",
+		},
+		{
+			description: "invalid JSON is rejected",
+			annotations: map[string]string{
+				PodTopologyManagerPolicyAnnotation: `{not-json`,
+			},
+			featureGateEnable: true,
+			expectedErr:       "unable to parse pod annotation",
+		},
+		{
+			description: "unknown policy name is rejected",
+			annotations: map[string]string{
+				PodTopologyManagerPolicyAnnotation: `{"policy":"made-up-policy"}`,
+			},
+			featureGateEnable: true,
+			expectedErr:       "requested unknown policy",
+		},
+	}
+
+	for _, tcase := range testCases {
+		t.Run(tcase.description, func(t *testing.T) {
+			featuregatetesting.SetFeatureGateDuringTest(t, utilfeature.DefaultFeatureGate, pkgfeatures.TopologyManagerPerPodPolicy, tcase.featureGateEnable)
+
+			spec, err := PodTopologySpecFromAnnotations(tcase.annotations)
+			if tcase.expectedErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tcase.expectedErr) {
+					t.Fatalf("expected error containing %q, got %v", tcase.expectedErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tcase.expectedSpec == nil {
+				if spec != nil {
+					t.Fatalf("expected nil spec, got %+v", spec)
+				}
+				return
+			}
+			if spec == nil {
+				t.Fatalf("expected spec %+v, got nil", tcase.expectedSpec)
+			}
+			if spec.Policy != tcase.expectedSpec.Policy ||
+				spec.Scope != tcase.expectedSpec.Scope ||
+				spec.PreferClosestNUMA != tcase.expectedSpec.PreferClosestNUMA ||
+				spec.MaxAllowableNUMANodes != tcase.expectedSpec.MaxAllowableNUMANodes ||
+				!intSlicesEqual(spec.AllowedNUMANodes, tcase.expectedSpec.AllowedNUMANodes) {
+				t.Errorf("expected spec %+v, got %+v", tcase.expectedSpec, spec)
+			}
+		})
+	}
+}
+
+func TestMergePodPolicyOptions(t *testing.T) {
+	defaults := PolicyOptions{
+		MaxAllowableNUMANodes: 8,
+	}
+
+	testCases := []struct {
+		description       string
+		spec              *PodTopologySpec
+		featureGate       featuregate.Feature
+		featureGateEnable bool
+		expectedOpts      PolicyOptions
+		expectedErr       string
+	}{
+		{
+			description:  "nil spec falls back to kubelet defaults",
+			spec:         nil,
+			expectedOpts: defaults,
+		},
+		{
+			description: "pod downgrades MaxAllowableNUMANodes",
+			spec: &PodTopologySpec{
+				MaxAllowableNUMANodes: 2,
+			},
+			expectedOpts: PolicyOptions{MaxAllowableNUMANodes: 2},
+		},
+		{
+			description: "pod requests AllowedNUMANodes",
+			spec: &PodTopologySpec{
+				AllowedNUMANodes: []int{0, 1},
+			},
+			expectedOpts: PolicyOptions{MaxAllowableNUMANodes: 8, AllowedNUMANodes: []int{0, 1}},
+		},
+		{
+			description: "pod requests PreferClosestNUMA when disabled kubelet-wide is still allowed, PreferClosestNUMANodes is a stable option",
+			spec: &PodTopologySpec{
+				PreferClosestNUMA: true,
+			},
+			expectedOpts: PolicyOptions{MaxAllowableNUMANodes: 8, PreferClosestNUMA: true},
+		},
+		{
+			description: "pod requests SingleNUMANodeExclusive while the alpha options gate is disabled",
+			spec: &PodTopologySpec{
+				SingleNUMANodeExclusive: true,
+			},
+			featureGate:       pkgfeatures.TopologyManagerPolicyAlphaOptions,
+			featureGateEnable: false,
+			expectedErr:       "alpha-level options not enabled",
+		},
+		{
+			description: "pod requests SingleNUMANodeExclusive while the alpha options gate is enabled",
+			spec: &PodTopologySpec{
+				SingleNUMANodeExclusive: true,
+			},
+			featureGate:       pkgfeatures.TopologyManagerPolicyAlphaOptions,
+			featureGateEnable: true,
+			expectedOpts:      PolicyOptions{MaxAllowableNUMANodes: 8, SingleNUMANodeExclusive: true},
+		},
+	}
+
+	for _, tcase := range testCases {
+		t.Run(tcase.description, func(t *testing.T) {
+			if tcase.featureGate != "" {
+				featuregatetesting.SetFeatureGateDuringTest(t, utilfeature.DefaultFeatureGate, tcase.featureGate, tcase.featureGateEnable)
+			}
+
+			opts, err := MergePodPolicyOptions(defaults, tcase.spec)
+			if tcase.expectedErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tcase.expectedErr) {
+					t.Fatalf("expected error containing %q, got %v", tcase.expectedErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if opts.PreferClosestNUMA != tcase.expectedOpts.PreferClosestNUMA ||
+				opts.MaxAllowableNUMANodes != tcase.expectedOpts.MaxAllowableNUMANodes ||
+				opts.SingleNUMANodeExclusive != tcase.expectedOpts.SingleNUMANodeExclusive ||
+				!intSlicesEqual(opts.AllowedNUMANodes, tcase.expectedOpts.AllowedNUMANodes) {
+				t.Errorf("expected %+v, got %+v", tcase.expectedOpts, opts)
+			}
+		})
+	}
+}
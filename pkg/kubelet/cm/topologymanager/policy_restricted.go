@@ -0,0 +1,46 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package topologymanager
+
+type restrictedPolicy struct{}
+
+var _ Policy = &restrictedPolicy{}
+
+// NewRestrictedPolicy returns a policy that rejects a pod unless all hint
+// providers agree on a preferred NUMANodeAffinity.
+func NewRestrictedPolicy() Policy {
+	return &restrictedPolicy{}
+}
+
+func (p *restrictedPolicy) Name() string {
+	return PolicyRestricted
+}
+
+func (p *restrictedPolicy) Merge(opts PolicyOptions, numaInfo *NUMAInfo, providersHints []map[string][]TopologyHint, tracker *numaNodeTracker) (TopologyHint, bool) {
+	hint := NewHintMerger(numaInfo, providersHints, p.Name(), opts).Merge()
+	return hint, p.canAdmitPodResult(opts, &hint, tracker)
+}
+
+func (p *restrictedPolicy) canAdmitPodResult(opts PolicyOptions, hint *TopologyHint, tracker *numaNodeTracker) bool {
+	if !hint.Preferred {
+		return false
+	}
+	if opts.SingleNUMANodeExclusive && tracker != nil && !tracker.canAdmit(*hint) {
+		return false
+	}
+	return true
+}
@@ -0,0 +1,303 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package topologymanager
+
+import (
+	"fmt"
+
+	cadvisorapi "github.com/google/cadvisor/info/v1"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+	kubeletconfig "k8s.io/kubernetes/pkg/kubelet/apis/config"
+	"k8s.io/kubernetes/pkg/kubelet/lifecycle"
+)
+
+// Manager interface is used by kubelet components to interact with the
+// topology manager.
+type Manager interface {
+	// PolicyName returns the name of the kubelet-wide policy in effect.
+	PolicyName() string
+	// GetAffinity returns the TopologyHint that was allocated for the given
+	// container.
+	GetAffinity(podUID string, containerName string) TopologyHint
+	// AddHintProvider registers a component whose hints should be consulted
+	// when admitting pods.
+	AddHintProvider(HintProvider)
+	// AddContainer records that the given container, belonging to pod, has
+	// been admitted, so the manager can track NUMA node usage.
+	AddContainer(pod *v1.Pod, container *v1.Container, containerID string) error
+	// RemoveContainer removes any state the manager was tracking for the
+	// given container.
+	RemoveContainer(containerID string) error
+	// Admit decides whether the given pod can be admitted, taking into
+	// account the kubelet-wide policy as well as any per-pod override the
+	// pod requested through the PodTopologyManagerPolicyAnnotation.
+	Admit(attrs *lifecycle.PodAdmitAttributes) lifecycle.PodAdmitResult
+}
+
+type manager struct {
+	// policies maps a policy name to the Policy implementation backing it.
+	// It is built once at construction time so that a per-pod override can
+	// select any of them without re-allocating policy state on every
+	// Admit call.
+	policies map[string]Policy
+
+	// defaultPolicyName is the kubelet-wide policy used for pods that do
+	// not carry a per-pod override.
+	defaultPolicyName string
+
+	// defaultOpts is the kubelet-wide PolicyOptions, parsed once from the
+	// --topology-manager-policy-options flag.
+	defaultOpts PolicyOptions
+
+	numaInfo *NUMAInfo
+
+	// numaTracker backs the SingleNUMANodeExclusive PolicyOption: it
+	// records, across all admitted pods, which NUMA nodes are currently
+	// held exclusively versus shared.
+	numaTracker *numaNodeTracker
+
+	hintProviders    []HintProvider
+	podTopologyHints map[string]map[string]TopologyHint
+
+	// containerPodUID and podContainerCount let RemoveContainer find which
+	// pod a containerID belonged to, and release that pod's numaTracker
+	// claims once its last container is removed.
+	containerPodUID   map[string]string
+	podContainerCount map[string]int
+}
+
+var _ Manager = &manager{}
+
+// NewManager creates a new TopologyManager based on the provided policy name
+// and policy options.
+func NewManager(topology []cadvisorapi.Node, topologyPolicyName string, topologyPolicyOptions map[string]string) (Manager, error) {
+	return newManager(topology, topologyPolicyName, topologyPolicyOptions, nil)
+}
+
+// NewManagerWithPolicyOptionsConfig is like NewManager, but additionally
+// accepts the structured TopologyManagerPolicyOptionsConfig. When
+// topologyPolicyOptionsConfig is non-nil, it takes precedence over
+// topologyPolicyOptions, matching
+// KubeletConfiguration.TopologyManagerPolicyOptionsConfig's documented
+// precedence over TopologyManagerPolicyOptions. It is a separate entry
+// point, rather than an added parameter on NewManager, so existing callers
+// of NewManager do not need to change.
+func NewManagerWithPolicyOptionsConfig(topology []cadvisorapi.Node, topologyPolicyName string, topologyPolicyOptions map[string]string, topologyPolicyOptionsConfig *kubeletconfig.TopologyManagerPolicyOptionsConfig) (Manager, error) {
+	return newManager(topology, topologyPolicyName, topologyPolicyOptions, topologyPolicyOptionsConfig)
+}
+
+func newManager(topology []cadvisorapi.Node, topologyPolicyName string, topologyPolicyOptions map[string]string, topologyPolicyOptionsConfig *kubeletconfig.TopologyManagerPolicyOptionsConfig) (Manager, error) {
+	var opts PolicyOptions
+	var err error
+	if topologyPolicyOptionsConfig != nil {
+		opts, err = NewPolicyOptionsFromConfig(topologyPolicyOptionsConfig)
+	} else {
+		opts, err = NewPolicyOptions(topologyPolicyOptions)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	numaInfo, err := NewNUMAInfo(topology, opts)
+	if err != nil {
+		return nil, fmt.Errorf("cannot discover NUMA topology: %w", err)
+	}
+
+	policies := map[string]Policy{
+		PolicyNone:           NewNonePolicy(),
+		PolicyBestEffort:     NewBestEffortPolicy(),
+		PolicyRestricted:     NewRestrictedPolicy(),
+		PolicySingleNumaNode: NewSingleNumaNodePolicy(),
+	}
+
+	if _, ok := policies[topologyPolicyName]; !ok {
+		return nil, fmt.Errorf("unknown policy: %q", topologyPolicyName)
+	}
+
+	klog.InfoS("Creating topology manager with policy per scope", "topologyPolicyName", topologyPolicyName)
+
+	return &manager{
+		policies:          policies,
+		defaultPolicyName: topologyPolicyName,
+		defaultOpts:       opts,
+		numaInfo:          numaInfo,
+		numaTracker:       newNUMANodeTracker(),
+		podTopologyHints:  map[string]map[string]TopologyHint{},
+		containerPodUID:   map[string]string{},
+		podContainerCount: map[string]int{},
+	}, nil
+}
+
+func (m *manager) PolicyName() string {
+	return m.defaultPolicyName
+}
+
+func (m *manager) GetAffinity(podUID string, containerName string) TopologyHint {
+	return m.podTopologyHints[podUID][containerName]
+}
+
+func (m *manager) AddHintProvider(h HintProvider) {
+	m.hintProviders = append(m.hintProviders, h)
+}
+
+// policyForPod resolves the Policy implementation, effective PolicyOptions,
+// and effective scope that should be used to admit the given pod, taking
+// into account any per-pod override carried in its annotations.
+func (m *manager) policyForPod(pod *v1.Pod) (Policy, PolicyOptions, string, error) {
+	spec, err := PodTopologySpecFromAnnotations(pod.Annotations)
+	if err != nil {
+		return nil, PolicyOptions{}, "", err
+	}
+
+	policyName := m.defaultPolicyName
+	if spec != nil && spec.Policy != "" {
+		policyName = spec.Policy
+	}
+
+	policy, ok := m.policies[policyName]
+	if !ok {
+		return nil, PolicyOptions{}, "", fmt.Errorf("pod %s/%s requested unknown topology manager policy %q", pod.Namespace, pod.Name, policyName)
+	}
+
+	opts, err := MergePodPolicyOptions(m.defaultOpts, spec)
+	if err != nil {
+		return nil, PolicyOptions{}, "", err
+	}
+
+	scope := TopologyScopeContainer
+	if spec != nil && spec.Scope != "" {
+		scope = spec.Scope
+	}
+
+	return policy, opts, scope, nil
+}
+
+func (m *manager) Admit(attrs *lifecycle.PodAdmitAttributes) lifecycle.PodAdmitResult {
+	pod := attrs.Pod
+
+	policy, opts, scope, err := m.policyForPod(pod)
+	if err != nil {
+		return lifecycle.PodAdmitResult{
+			Admit:   false,
+			Reason:  "TopologyAffinityError",
+			Message: err.Error(),
+		}
+	}
+
+	containers := append(append([]v1.Container{}, pod.Spec.InitContainers...), pod.Spec.Containers...)
+
+	if scope == TopologyScopePod {
+		return m.admitPodScope(pod, containers, policy, opts)
+	}
+	return m.admitContainerScope(pod, containers, policy, opts)
+}
+
+// admitContainerScope merges hints independently for each container in the
+// pod, so each container may end up with a different NUMA affinity.
+func (m *manager) admitContainerScope(pod *v1.Pod, containers []v1.Container, policy Policy, opts PolicyOptions) lifecycle.PodAdmitResult {
+	for _, container := range containers {
+		var providersHints []map[string][]TopologyHint
+		for _, provider := range m.hintProviders {
+			providersHints = append(providersHints, provider.GetTopologyHints(pod, &container))
+		}
+
+		bestHint, admit := policy.Merge(opts, m.numaInfo, providersHints, m.numaTracker)
+		if !admit {
+			return lifecycle.PodAdmitResult{
+				Admit:   false,
+				Reason:  "TopologyAffinityError",
+				Message: fmt.Sprintf("Resources cannot be allocated with topology locality for pod %s/%s, container %s, policy %q", pod.Namespace, pod.Name, container.Name, policy.Name()),
+			}
+		}
+
+		m.recordHint(pod, container.Name, bestHint)
+	}
+
+	return lifecycle.PodAdmitResult{Admit: true}
+}
+
+// admitPodScope merges hints once across every container in the pod and
+// assigns the resulting affinity to all of them, so no container in the
+// pod ends up pinned to a NUMA node another container wasn't also granted.
+func (m *manager) admitPodScope(pod *v1.Pod, containers []v1.Container, policy Policy, opts PolicyOptions) lifecycle.PodAdmitResult {
+	var providersHints []map[string][]TopologyHint
+	for _, container := range containers {
+		for _, provider := range m.hintProviders {
+			providersHints = append(providersHints, provider.GetTopologyHints(pod, &container))
+		}
+	}
+
+	bestHint, admit := policy.Merge(opts, m.numaInfo, providersHints, m.numaTracker)
+	if !admit {
+		return lifecycle.PodAdmitResult{
+			Admit:   false,
+			Reason:  "TopologyAffinityError",
+			Message: fmt.Sprintf("Resources cannot be allocated with topology locality for pod %s/%s, policy %q", pod.Namespace, pod.Name, policy.Name()),
+		}
+	}
+
+	for _, container := range containers {
+		m.recordHint(pod, container.Name, bestHint)
+	}
+
+	return lifecycle.PodAdmitResult{Admit: true}
+}
+
+// recordHint stores the TopologyHint allocated to the given container of
+// pod so that later GetAffinity/AddContainer calls can retrieve it.
+func (m *manager) recordHint(pod *v1.Pod, containerName string, hint TopologyHint) {
+	if _, ok := m.podTopologyHints[string(pod.UID)]; !ok {
+		m.podTopologyHints[string(pod.UID)] = map[string]TopologyHint{}
+	}
+	m.podTopologyHints[string(pod.UID)][containerName] = hint
+}
+
+func (m *manager) AddContainer(pod *v1.Pod, container *v1.Container, containerID string) error {
+	for _, provider := range m.hintProviders {
+		if err := provider.Allocate(pod, container); err != nil {
+			return err
+		}
+	}
+
+	podUID := string(pod.UID)
+	if hint, ok := m.podTopologyHints[podUID][container.Name]; ok {
+		m.numaTracker.record(podUID, hint)
+	}
+
+	m.containerPodUID[containerID] = podUID
+	m.podContainerCount[podUID]++
+
+	return nil
+}
+
+func (m *manager) RemoveContainer(containerID string) error {
+	podUID, ok := m.containerPodUID[containerID]
+	if !ok {
+		return nil
+	}
+	delete(m.containerPodUID, containerID)
+
+	m.podContainerCount[podUID]--
+	if m.podContainerCount[podUID] <= 0 {
+		delete(m.podContainerCount, podUID)
+		m.numaTracker.release(podUID)
+		delete(m.podTopologyHints, podUID)
+	}
+
+	return nil
+}